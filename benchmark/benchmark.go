@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     benchmark/benchmark.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+// Package benchmark runs variants of the solution several times and calculates
+// the statistics of their run times, like hyperfine does.
+//
+// A variant is either run in the same process, or as a subprocess running
+// `onebrc run --variant=NAME`, which includes the start of the program and
+// writing the output, like the timings of hyperfine.
+package benchmark
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/variants"
+)
+
+// Options are the options of running a benchmark.
+type Options struct {
+	// Runs is the number of measured runs, at least 1.
+	Runs int
+	// Warmup is the number of runs before the measured ones, which aren't
+	// measured.
+	Warmup int
+	// DropCache drops the page cache before each run, see DropPageCache.
+	DropCache bool
+	// Subprocess runs the variant as a subprocess of Executable instead of in
+	// the same process.
+	Subprocess bool
+	// Executable is the onebrc executable to run as a subprocess. The default
+	// is the running executable.
+	Executable string
+}
+
+// Result is the result of benchmarking a single variant, all times are in
+// seconds.
+type Result struct {
+	Variant string    `json:"variant"`
+	Mean    float64   `json:"mean"`
+	StdDev  float64   `json:"stddev"`
+	Median  float64   `json:"median"`
+	Min     float64   `json:"min"`
+	Max     float64   `json:"max"`
+	Times   []float64 `json:"times"`
+}
+
+// Run benchmarks `variant` processing the file `fileName`.
+func Run(variant variants.Variant, fileName string, opts Options) (Result, error) {
+	runOnce := func() error {
+		_, err := variant.Run(fileName)
+		return err
+	}
+	if opts.Subprocess {
+		executable := opts.Executable
+		if executable == "" {
+			var err error
+			executable, err = os.Executable()
+			if err != nil {
+				return Result{}, fmt.Errorf("error getting the executable to run: %w", err)
+			}
+		}
+		runOnce = func() error {
+			return runSubprocess(executable, variant.Name(), fileName)
+		}
+	}
+
+	times := make([]float64, 0, opts.Runs)
+	for run := range opts.Warmup + max(opts.Runs, 1) {
+		if opts.DropCache {
+			err := DropPageCache()
+			if err != nil {
+				return Result{}, err
+			}
+		}
+		// Don't measure the garbage of the run before.
+		runtime.GC()
+
+		start := time.Now()
+		err := runOnce()
+		elapsed := time.Since(start)
+		if err != nil {
+			return Result{}, fmt.Errorf("error running variant '%s': %w", variant.Name(), err)
+		}
+		if run >= opts.Warmup {
+			times = append(times, elapsed.Seconds())
+		}
+	}
+	return newResult(variant.Name(), times), nil
+}
+
+// runSubprocess runs `executable run --variant=NAME fileName`, discarding the
+// output.
+func runSubprocess(executable string, variantName string, fileName string) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command(executable, "run", "--variant="+variantName, fileName)
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// The file to write to, to drop the page cache of Linux.
+const dropCachesFile = "/proc/sys/vm/drop_caches"
+
+// DropPageCache drops the page cache, so the data file has to be read from
+// disk again. This only works on Linux and needs root permissions.
+func DropPageCache() error {
+	syscall.Sync()
+	// 1 frees only the page cache, not the dentries and inodes.
+	err := os.WriteFile(dropCachesFile, []byte("1"), 0)
+	if err != nil {
+		return fmt.Errorf("error dropping the page cache: %w", err)
+	}
+	return nil
+}
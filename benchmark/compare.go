@@ -0,0 +1,254 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     benchmark/compare.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package benchmark
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// CompareOptions are the options of comparing the results of two revisions.
+type CompareOptions struct {
+	// Alpha is the significance level of the t-test, like 0.05.
+	Alpha float64
+	// Threshold is the minimum relative change of the mean to report, like
+	// 0.02 for 2%.
+	Threshold float64
+}
+
+// Comparison is the comparison of the results of a variant on the same host
+// and dataset at two revisions. The run times of all records of a revision
+// are pooled.
+type Comparison struct {
+	Variant    string
+	Host       string
+	Dataset    string
+	Size       int64
+	Subprocess bool
+	DropCache  bool
+	Base       Result
+	New        Result
+	// Change is the relative change of the mean, positive if the new
+	// revision is slower.
+	Change float64
+	// PValue is the two-sided p-value of Welch's t-test, NaN if there are
+	// less than 2 runs of a revision.
+	PValue float64
+	// Regression is true if the new revision is significantly slower.
+	Regression bool
+	// Improvement is true if the new revision is significantly faster.
+	Improvement bool
+}
+
+// recordKey identifies the results of the same benchmark of different
+// revisions.
+type recordKey struct {
+	variant    string
+	host       string
+	dataset    string
+	size       int64
+	subprocess bool
+	dropCache  bool
+}
+
+func (r Record) key() recordKey {
+	return recordKey{
+		variant:    r.Variant,
+		host:       r.Host,
+		dataset:    r.Dataset,
+		size:       r.Size,
+		subprocess: r.Subprocess,
+		dropCache:  r.DropCache,
+	}
+}
+
+// matchesRevision returns true, if the commit of a record `commit` is the
+// revision `revision`, which may be an abbreviated commit hash. A dirty
+// working tree only matches a dirty revision.
+func matchesRevision(commit string, revision string) bool {
+	commit, commitDirty := strings.CutSuffix(commit, dirtySuffix)
+	revision, revisionDirty := strings.CutSuffix(revision, dirtySuffix)
+	return revision != "" && commitDirty == revisionDirty && strings.HasPrefix(commit, revision)
+}
+
+// CompareRevisions compares the results of the revision `baseRevision` with
+// the ones of `newRevision`, for every benchmark both revisions have results
+// of. The revisions are commit hashes, see ResolveRevision.
+func CompareRevisions(records []Record, baseRevision string, newRevision string, opts CompareOptions) []Comparison {
+	baseTimes := make(map[recordKey][]float64)
+	newTimes := make(map[recordKey][]float64)
+	for _, record := range records {
+		if matchesRevision(record.Commit, baseRevision) {
+			baseTimes[record.key()] = append(baseTimes[record.key()], record.Times...)
+		}
+		if matchesRevision(record.Commit, newRevision) {
+			newTimes[record.key()] = append(newTimes[record.key()], record.Times...)
+		}
+	}
+
+	var comparisons []Comparison
+	for key, times := range newTimes {
+		if _, ok := baseTimes[key]; !ok {
+			continue
+		}
+		comparison := Comparison{
+			Variant:    key.variant,
+			Host:       key.host,
+			Dataset:    key.dataset,
+			Size:       key.size,
+			Subprocess: key.subprocess,
+			DropCache:  key.dropCache,
+			Base:       newResult(key.variant, baseTimes[key]),
+			New:        newResult(key.variant, times),
+		}
+		comparison.Change = comparison.New.Mean/comparison.Base.Mean - 1
+		comparison.PValue = welchPValue(comparison.Base, comparison.New)
+		significant := comparison.PValue < opts.Alpha
+		comparison.Regression = significant && comparison.Change > opts.Threshold
+		comparison.Improvement = significant && comparison.Change < -opts.Threshold
+		comparisons = append(comparisons, comparison)
+	}
+	slices.SortFunc(comparisons, func(a, b Comparison) int {
+		return cmp.Or(
+			cmp.Compare(a.Host, b.Host),
+			cmp.Compare(a.Dataset, b.Dataset),
+			cmp.Compare(a.Size, b.Size),
+			cmp.Compare(a.Variant, b.Variant),
+			compareBool(a.Subprocess, b.Subprocess),
+			compareBool(a.DropCache, b.DropCache),
+		)
+	})
+	return comparisons
+}
+
+func compareBool(a bool, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+	return -1
+}
+
+func (c Comparison) String() string {
+	verdict := ""
+	switch {
+	case c.Regression:
+		verdict = ", REGRESSION"
+	case c.Improvement:
+		verdict = ", improvement"
+	}
+	mode := ""
+	if c.Subprocess {
+		mode += ", subprocess"
+	}
+	if c.DropCache {
+		mode += ", drop cache"
+	}
+	return fmt.Sprintf("%s (%s, %s%s): %s -> %s, %+.1f %%, p = %.4f%s",
+		c.Variant, c.Host, c.Dataset, mode,
+		formatTime(c.Base), formatTime(c.New), c.Change*100, c.PValue, verdict)
+}
+
+// formatTime returns the mean and standard deviation of `result`.
+func formatTime(result Result) string {
+	unit, factor := timeUnit(result.Mean)
+	return fmt.Sprintf("%.3f %s ± %.3f %s", result.Mean*factor, unit, result.StdDev*factor, unit)
+}
+
+// welchPValue returns the two-sided p-value of Welch's t-test of the means of
+// `a` and `b`, or NaN if one of them has less than 2 runs.
+func welchPValue(a Result, b Result) float64 {
+	numA := float64(len(a.Times))
+	numB := float64(len(b.Times))
+	if numA < 2 || numB < 2 {
+		return math.NaN()
+	}
+	varA := a.StdDev * a.StdDev / numA
+	varB := b.StdDev * b.StdDev / numB
+	if varA+varB == 0 {
+		if a.Mean == b.Mean {
+			return 1
+		}
+		return 0
+	}
+	t := (a.Mean - b.Mean) / math.Sqrt(varA+varB)
+	// The Welch-Satterthwaite degrees of freedom.
+	df := (varA + varB) * (varA + varB) / (varA*varA/(numA-1) + varB*varB/(numB-1))
+	return studentTPValue(t, df)
+}
+
+// studentTPValue returns the two-sided p-value of `t` of Student's t
+// distribution with `df` degrees of freedom.
+func studentTPValue(t float64, df float64) float64 {
+	return incompleteBeta(df/2, 0.5, df/(df+t*t))
+}
+
+// incompleteBeta returns the regularized incomplete beta function I_x(a, b),
+// see Numerical Recipes, chapter 6.4.
+func incompleteBeta(a float64, b float64, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	lgammaAB, _ := math.Lgamma(a + b)
+	lgammaA, _ := math.Lgamma(a)
+	lgammaB, _ := math.Lgamma(b)
+	front := math.Exp(lgammaAB - lgammaA - lgammaB + a*math.Log(x) + b*math.Log(1-x))
+	// The continued fraction converges fast only for x < (a+1)/(a+b+2),
+	// else use the symmetry I_x(a, b) = 1 - I_(1-x)(b, a).
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(a, b, x) / a
+	}
+	return 1 - front*betaContinuedFraction(b, a, 1-x)/b
+}
+
+// betaContinuedFraction evaluates the continued fraction of the incomplete
+// beta function using Lentz's method.
+func betaContinuedFraction(a float64, b float64, x float64) float64 {
+	const (
+		maxIterations = 300
+		epsilon       = 1e-15
+		tiny          = 1e-300
+	)
+	notTiny := func(v float64) float64 {
+		if math.Abs(v) < tiny {
+			return tiny
+		}
+		return v
+	}
+
+	c := 1.0
+	d := 1 / notTiny(1-(a+b)*x/(a+1))
+	h := d
+	for m := 1.0; m <= maxIterations; m++ {
+		// The even step.
+		coeff := m * (b - m) * x / ((a + 2*m - 1) * (a + 2*m))
+		d = 1 / notTiny(1+coeff*d)
+		c = notTiny(1 + coeff/c)
+		h *= d * c
+		// The odd step.
+		coeff = -(a + m) * (a + b + m) * x / ((a + 2*m) * (a + 2*m + 1))
+		d = 1 / notTiny(1+coeff*d)
+		c = notTiny(1 + coeff/c)
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return h
+}
@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     benchmark/compare_test.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package benchmark
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStudentTPValue(t *testing.T) {
+	for _, tc := range []struct {
+		t      float64
+		df     float64
+		pValue float64
+	}{
+		{t: 0, df: 5, pValue: 1},
+		// The Cauchy distribution.
+		{t: 1, df: 1, pValue: 0.5},
+		// The critical values of the tables.
+		{t: 2.228, df: 10, pValue: 0.05},
+		{t: -2.228, df: 10, pValue: 0.05},
+		{t: 3.169, df: 10, pValue: 0.01},
+		{t: 1.960, df: 1e6, pValue: 0.05},
+	} {
+		pValue := studentTPValue(tc.t, tc.df)
+		if math.Abs(pValue-tc.pValue) > 1e-4 {
+			t.Errorf("t = %g, df = %g: got p = %g, want %g", tc.t, tc.df, pValue, tc.pValue)
+		}
+	}
+}
+
+func TestWelchPValue(t *testing.T) {
+	a := newResult("a", []float64{1, 2, 3, 4, 5})
+	b := newResult("b", []float64{3, 4, 5, 6, 7})
+	// t = -2 with 8 degrees of freedom.
+	if pValue := welchPValue(a, b); math.Abs(pValue-0.08052) > 1e-4 {
+		t.Errorf("got p = %g, want 0.08052", pValue)
+	}
+	if pValue := welchPValue(a, newResult("c", []float64{1})); !math.IsNaN(pValue) {
+		t.Errorf("got p = %g for a single run, want NaN", pValue)
+	}
+}
+
+func TestMatchesRevision(t *testing.T) {
+	for _, tc := range []struct {
+		commit   string
+		revision string
+		matches  bool
+	}{
+		{"1234567890", "1234567890", true},
+		{"1234567890", "1234567", true},
+		{"1234567890", "1234568", false},
+		{"1234567890-dirty", "1234567", false},
+		{"1234567890-dirty", "1234567-dirty", true},
+		{"1234567890", "1234567-dirty", false},
+		{"1234567890", "", false},
+	} {
+		if matchesRevision(tc.commit, tc.revision) != tc.matches {
+			t.Errorf("commit %s, revision %s: want %t", tc.commit, tc.revision, tc.matches)
+		}
+	}
+}
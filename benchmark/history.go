@@ -0,0 +1,172 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     benchmark/history.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package benchmark
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultHistoryFile is the default history file of the benchmark results.
+const DefaultHistoryFile = "bench_history.jsonl"
+
+// dirtySuffix is appended to the commit of a working tree with uncommitted
+// changes, like `git describe --dirty` does.
+const dirtySuffix = "-dirty"
+
+// Record is the result of benchmarking a variant, saved as a single line of
+// JSON in the history file. A result is identified by the commit, the
+// variant, the host and the dataset.
+type Record struct {
+	Commit     string    `json:"commit"`
+	Date       time.Time `json:"date"`
+	Host       string    `json:"host"`
+	Dataset    string    `json:"dataset"`
+	Size       int64     `json:"size"`
+	Subprocess bool      `json:"subprocess"`
+	DropCache  bool      `json:"drop_cache"`
+	Result
+}
+
+// Records returns the records of all results of the report, of the commit
+// `commit` and run on the host `host` at `date`. The dataset is the file name
+// without the directory.
+func (r Report) Records(commit string, host string, date time.Time) []Record {
+	records := make([]Record, 0, len(r.Results))
+	for _, result := range r.Results {
+		records = append(records, Record{
+			Commit:     commit,
+			Date:       date,
+			Host:       host,
+			Dataset:    filepath.Base(r.File),
+			Size:       r.Size,
+			Subprocess: r.Subprocess,
+			DropCache:  r.DropCache,
+			Result:     result,
+		})
+	}
+	return records
+}
+
+// AppendHistory appends `records` to the history file `fileName`, which is
+// created if it doesn't exist.
+func AppendHistory(fileName string, records []Record) error {
+	file, err := os.OpenFile(fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("error opening history file '%s': %w", fileName, err)
+	}
+	// Write all records at once, so a failed write doesn't leave only some
+	// of them.
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	for _, record := range records {
+		err = encoder.Encode(record)
+		if err != nil {
+			file.Close()
+			return fmt.Errorf("error encoding the record of variant '%s': %w", record.Variant, err)
+		}
+	}
+	_, err = file.Write(buffer.Bytes())
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("error writing history file '%s': %w", fileName, err)
+	}
+	return nil
+}
+
+// ReadHistoryFile returns the records of the history file `fileName`.
+func ReadHistoryFile(fileName string) ([]Record, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("error opening history file '%s': %w", fileName, err)
+	}
+	defer file.Close()
+
+	records, err := ReadHistory(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading history file '%s': %w", fileName, err)
+	}
+	return records, nil
+}
+
+// ReadHistory returns the records read from `r`, one JSON object per line.
+// Empty lines are skipped.
+func ReadHistory(r io.Reader) ([]Record, error) {
+	var records []Record
+	reader := bufio.NewReader(r)
+	lineNum := 0
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			lineNum++
+		}
+		if len(bytes.TrimSpace(line)) > 0 {
+			var record Record
+			jsonErr := json.Unmarshal(line, &record)
+			if jsonErr != nil {
+				return nil, fmt.Errorf("invalid record in line %d: %w", lineNum, jsonErr)
+			}
+			records = append(records, record)
+		}
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// GitCommit returns the hash of the commit checked out in the working
+// directory, with `-dirty` appended if there are uncommitted changes.
+func GitCommit() (string, error) {
+	output, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("error getting the git commit: %w", err)
+	}
+	commit := strings.TrimSpace(string(output))
+
+	status, err := exec.Command("git", "status", "--porcelain", "--untracked-files=no").Output()
+	if err != nil {
+		return "", fmt.Errorf("error getting the git status: %w", err)
+	}
+	if len(bytes.TrimSpace(status)) > 0 {
+		commit += dirtySuffix
+	}
+	return commit, nil
+}
+
+// ResolveRevision returns the commit hash of the git revision `revision`,
+// like `HEAD~1`, keeping a `-dirty` suffix. If git doesn't know the revision,
+// it is returned unchanged.
+func ResolveRevision(revision string) string {
+	name, dirty := strings.CutSuffix(revision, dirtySuffix)
+	output, err := exec.Command("git", "rev-parse", "--verify", "--quiet", name+"^{commit}").Output()
+	if err != nil {
+		return revision
+	}
+	commit := strings.TrimSpace(string(output))
+	if dirty {
+		commit += dirtySuffix
+	}
+	return commit
+}
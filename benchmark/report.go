@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     benchmark/report.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package benchmark
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+)
+
+// Report contains the results of all benchmarked variants.
+type Report struct {
+	File       string   `json:"file"`
+	Size       int64    `json:"size"`
+	Runs       int      `json:"runs"`
+	Warmup     int      `json:"warmup"`
+	Subprocess bool     `json:"subprocess"`
+	DropCache  bool     `json:"drop_cache"`
+	Results    []Result `json:"results"`
+}
+
+// newResult returns the result of the run times `times` in seconds.
+func newResult(variantName string, times []float64) Result {
+	result := Result{Variant: variantName, Times: times}
+	if len(times) == 0 {
+		return result
+	}
+
+	sum := 0.0
+	for _, t := range times {
+		sum += t
+	}
+	result.Mean = sum / float64(len(times))
+	// The sample standard deviation, like hyperfine.
+	if len(times) > 1 {
+		squares := 0.0
+		for _, t := range times {
+			squares += (t - result.Mean) * (t - result.Mean)
+		}
+		result.StdDev = math.Sqrt(squares / float64(len(times)-1))
+	}
+
+	sorted := slices.Clone(times)
+	slices.Sort(sorted)
+	result.Min = sorted[0]
+	result.Max = sorted[len(sorted)-1]
+	result.Median = sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		result.Median = (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+	}
+	return result
+}
+
+// Speedup is how many times faster the fastest variant is than another one.
+type Speedup struct {
+	Variant string
+	Factor  float64
+	StdDev  float64
+}
+
+// Speedups returns the fastest result and the speedups of it compared to all
+// other results, from the fastest to the slowest.
+func (r Report) Speedups() (Result, []Speedup) {
+	if len(r.Results) == 0 {
+		return Result{}, nil
+	}
+	fastest := slices.MinFunc(r.Results, func(a, b Result) int {
+		return compareFloat(a.Mean, b.Mean)
+	})
+	speedups := make([]Speedup, 0, len(r.Results)-1)
+	for _, result := range r.Results {
+		if result.Variant == fastest.Variant {
+			continue
+		}
+		factor := result.Mean / fastest.Mean
+		// The propagation of the uncertainties of both means.
+		stdDev := factor * math.Sqrt(math.Pow(result.StdDev/result.Mean, 2)+math.Pow(fastest.StdDev/fastest.Mean, 2))
+		speedups = append(speedups, Speedup{Variant: result.Variant, Factor: factor, StdDev: stdDev})
+	}
+	slices.SortStableFunc(speedups, func(a, b Speedup) int {
+		return compareFloat(a.Factor, b.Factor)
+	})
+	return fastest, speedups
+}
+
+func compareFloat(a float64, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Print prints the result like hyperfine, `number` is the number of the
+// benchmark.
+func (r Result) Print(w io.Writer, number int) error {
+	unit, factor := timeUnit(r.Mean)
+	_, err := fmt.Fprintf(w, "Benchmark %d: %s\n"+
+		"  Time (mean ± σ):   %8.3f %s ± %7.3f %s\n"+
+		"  Range (min … max): %8.3f %s … %7.3f %s    %d runs\n\n",
+		number, r.Variant,
+		r.Mean*factor, unit, r.StdDev*factor, unit,
+		r.Min*factor, unit, r.Max*factor, unit, len(r.Times))
+	return err
+}
+
+// timeUnit returns the unit to print the time `seconds` in and the factor to
+// convert seconds to it.
+func timeUnit(seconds float64) (string, float64) {
+	if seconds < 1 {
+		return "ms", 1000
+	}
+	return "s", 1
+}
+
+// PrintSummary prints the speedups of the fastest variant like hyperfine.
+func (r Report) PrintSummary(w io.Writer) error {
+	fastest, speedups := r.Speedups()
+	if len(speedups) == 0 {
+		return nil
+	}
+	_, err := fmt.Fprintf(w, "Summary\n  %s ran\n", fastest.Variant)
+	if err != nil {
+		return err
+	}
+	for _, speedup := range speedups {
+		_, err = fmt.Fprintf(w, "    %6.2f ± %.2f times faster than %s\n", speedup.Factor, speedup.StdDev, speedup.Variant)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
@@ -0,0 +1,250 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     cmd/onebrc/bench.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/benchmark"
+	"github.com/Release-Candidate/1-billion-row-challenge/variants"
+)
+
+func benchCommand(args []string) int {
+	if len(args) > 0 && args[0] == "compare" {
+		return benchCompareCommand(args[1:])
+	}
+
+	flags := flag.NewFlagSet("bench", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: onebrc bench [options] <data file>")
+		fmt.Fprintln(flags.Output(), "       onebrc bench compare [options] <base revision> <new revision>")
+		fmt.Fprintln(flags.Output())
+		fmt.Fprintln(flags.Output(), "Runs the variants several times on the data file and prints the mean, standard")
+		fmt.Fprintln(flags.Output(), "deviation, minimum and maximum of the run times and how much faster the fastest")
+		fmt.Fprintln(flags.Output(), "variant is, like hyperfine. The results are appended to the history file, with")
+		fmt.Fprintln(flags.Output(), "the git commit - empty outside of a git checkout - and the host name.")
+		fmt.Fprintln(flags.Output(), "See `onebrc bench compare -h`.")
+		fmt.Fprintln(flags.Output())
+		fmt.Fprintln(flags.Output(), "Options:")
+		flags.PrintDefaults()
+	}
+	variantNames := flags.String("variants", variants.Default, "the comma separated `names` of the variants to run, `all` runs all variants")
+	runs := flags.Int("runs", 5, "the `number` of measured runs of each variant")
+	warmup := flags.Int("warmup", 1, "the `number` of runs before the measured ones")
+	dropCache := flags.Bool("drop-cache", false, "drop the page cache before each run, needs root permissions on Linux")
+	subprocess := flags.Bool("subprocess", false, "run each variant as a subprocess `onebrc run --variant=NAME`, instead of in this process")
+	jsonFile := flags.String("json", "", "write the results as JSON to the `file`, `-` is stdout")
+	historyFile := flags.String("history", benchmark.DefaultHistoryFile, "append the results to the history `file`, an empty name disables the history")
+	err := flags.Parse(args)
+	if err != nil {
+		return 1
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 1
+	}
+	fileName := flags.Arg(0)
+	if *runs < 1 || *warmup < 0 {
+		fmt.Fprintln(os.Stderr, "Error: --runs must be at least 1 and --warmup not negative")
+		return 1
+	}
+
+	benchVariants, err := parseVariants(*variantNames)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 1
+	}
+
+	info, err := os.Stat(fileName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+
+	if *dropCache {
+		err = benchmark.DropPageCache()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s, running with the page cache\n", err)
+			*dropCache = false
+		}
+	}
+
+	// The results are printed to stderr, if the JSON is written to stdout.
+	var output io.Writer = os.Stdout
+	if *jsonFile == "-" {
+		output = os.Stderr
+	}
+
+	opts := benchmark.Options{
+		Runs:       *runs,
+		Warmup:     *warmup,
+		DropCache:  *dropCache,
+		Subprocess: *subprocess,
+	}
+	report := benchmark.Report{
+		File:       fileName,
+		Size:       info.Size(),
+		Runs:       *runs,
+		Warmup:     *warmup,
+		Subprocess: *subprocess,
+		DropCache:  *dropCache,
+	}
+	for idx, variant := range benchVariants {
+		result, err := benchmark.Run(variant, fileName, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+		result.Print(output, idx+1)
+		report.Results = append(report.Results, result)
+	}
+	report.PrintSummary(output)
+
+	if *jsonFile != "" {
+		err = writeJSONReport(*jsonFile, report)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+	}
+
+	if *historyFile != "" {
+		err = appendHistory(*historyFile, report)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+	}
+	return 0
+}
+
+// appendHistory appends the results of `report` to the history file
+// `fileName`, with the current git commit and host name. Outside of a git
+// checkout, the commit is empty.
+func appendHistory(fileName string, report benchmark.Report) error {
+	commit, err := benchmark.GitCommit()
+	if err != nil {
+		// Like a copy of the sources without `.git` or git not installed.
+		fmt.Fprintf(os.Stderr, "Warning: %s, saving the results without a commit\n", err)
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("error getting the host name: %w, can't write the history", err)
+	}
+	return benchmark.AppendHistory(fileName, report.Records(commit, host, time.Now()))
+}
+
+func benchCompareCommand(args []string) int {
+	flags := flag.NewFlagSet("bench compare", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: onebrc bench compare [options] <base revision> <new revision>")
+		fmt.Fprintln(flags.Output())
+		fmt.Fprintln(flags.Output(), "Compares the results of two git revisions in the history file written by")
+		fmt.Fprintln(flags.Output(), "`onebrc bench`, for each variant, host and dataset benchmarked at both. A slower")
+		fmt.Fprintln(flags.Output(), "mean is a regression if Welch's t-test is significant. A revision is anything")
+		fmt.Fprintln(flags.Output(), "git knows, like `HEAD~1`, or an abbreviated commit hash. Append `-dirty` for the")
+		fmt.Fprintln(flags.Output(), "results of uncommitted changes. Exits with 3 if there are regressions.")
+		fmt.Fprintln(flags.Output())
+		fmt.Fprintln(flags.Output(), "Options:")
+		flags.PrintDefaults()
+	}
+	historyFile := flags.String("history", benchmark.DefaultHistoryFile, "the history `file` to read")
+	alpha := flags.Float64("alpha", 0.05, "the significance `level` of the t-test")
+	threshold := flags.Float64("threshold", 0, "the minimum change of the mean in `percent` to report as regression or improvement")
+	err := flags.Parse(args)
+	if err != nil {
+		return 1
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 1
+	}
+	if *alpha <= 0 || *alpha >= 1 || *threshold < 0 {
+		fmt.Fprintln(os.Stderr, "Error: --alpha must be between 0 and 1 and --threshold not negative")
+		return 1
+	}
+
+	records, err := benchmark.ReadHistoryFile(*historyFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+
+	baseRevision := benchmark.ResolveRevision(flags.Arg(0))
+	newRevision := benchmark.ResolveRevision(flags.Arg(1))
+	comparisons := benchmark.CompareRevisions(records, baseRevision, newRevision, benchmark.CompareOptions{
+		Alpha:     *alpha,
+		Threshold: *threshold / 100,
+	})
+	if len(comparisons) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no benchmark has results of both '%s' and '%s'\n", flags.Arg(0), flags.Arg(1))
+		return 2
+	}
+
+	regressions := 0
+	improvements := 0
+	for _, comparison := range comparisons {
+		fmt.Println(comparison)
+		if comparison.Regression {
+			regressions++
+		}
+		if comparison.Improvement {
+			improvements++
+		}
+	}
+	fmt.Printf("%d comparisons: %d regressions, %d improvements\n", len(comparisons), regressions, improvements)
+	if regressions > 0 {
+		return 3
+	}
+	return 0
+}
+
+// parseVariants returns the variants of the comma separated list `names`, or
+// all variants if it is `all`.
+func parseVariants(names string) ([]variants.Variant, error) {
+	if names == "all" {
+		return variants.All(), nil
+	}
+	var result []variants.Variant
+	for _, name := range strings.Split(names, ",") {
+		variant, err := variants.Get(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, variant)
+	}
+	return result, nil
+}
+
+// writeJSONReport writes `report` as JSON to the file `fileName`, `-` is
+// stdout.
+func writeJSONReport(fileName string, report benchmark.Report) error {
+	if fileName == "-" {
+		return report.WriteJSON(os.Stdout)
+	}
+	file, err := os.Create(fileName)
+	if err != nil {
+		return fmt.Errorf("error creating JSON file '%s': %w", fileName, err)
+	}
+	err = report.WriteJSON(file)
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("error writing JSON file '%s': %w", fileName, err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     cmd/onebrc/bench_test.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/benchmark"
+)
+
+// TestBenchWithoutGit checks that `onebrc bench` saves the results with an
+// empty commit, if it isn't run in a git checkout.
+func TestBenchWithoutGit(t *testing.T) {
+	dir := t.TempDir()
+	// git fails, even if the temporary directory is inside of a checkout.
+	t.Setenv("GIT_DIR", filepath.Join(dir, "no-git"))
+	_, err := benchmark.GitCommit()
+	if err == nil {
+		t.Fatal("got a git commit, want an error")
+	}
+
+	dataFile := filepath.Join(dir, "measurements.txt")
+	err = os.WriteFile(dataFile, []byte("Hamburg;12.0\nBulawayo;8.9\nHamburg;-3.4\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	historyFile := filepath.Join(dir, "history.jsonl")
+	exitCode := benchCommand([]string{"--runs=2", "--warmup=0", "--history=" + historyFile, dataFile})
+	if exitCode != 0 {
+		t.Fatalf("got exit code %d, want 0", exitCode)
+	}
+
+	records, err := benchmark.ReadHistoryFile(historyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Commit != "" || records[0].Dataset != "measurements.txt" {
+		t.Errorf("got records %+v, want a single one of measurements.txt with an empty commit", records)
+	}
+}
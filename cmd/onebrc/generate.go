@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     cmd/onebrc/generate.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/generate"
+	"github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+)
+
+func generateCommand(args []string) int {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: onebrc generate --rows=N [options]")
+		fmt.Fprintln(flags.Output())
+		fmt.Fprintln(flags.Output(), "Generates a measurements file, like create_measurements.py. The same seed always")
+		fmt.Fprintln(flags.Output(), "generates the same data, regardless of the number of workers.")
+		fmt.Fprintln(flags.Output())
+		fmt.Fprintln(flags.Output(), "Options:")
+		flags.PrintDefaults()
+	}
+	rowsStr := flags.String("rows", "", "the `number` of lines to generate, like 1_000_000_000")
+	outFile := flags.String("out", "measurements.txt", "the `file` to write the data to, `-` is stdout")
+	seed := flags.Uint64("seed", 0, "the `seed` of the random number generators")
+	workers := flags.Int("workers", 0, "the `number` of goroutines generating the data, the default is the number of cores")
+	distributionName := flags.String("distribution", generate.DistributionUniform.String(),
+		"the distribution of the temperatures, one of: "+strings.Join(generate.DistributionNames(), ", "))
+	stdDev := flags.Float64("stddev", 10, "the standard deviation of the gaussian distribution in `degrees`")
+	profileName := flags.String("profile", generate.ProfileDefault.String(),
+		"the profile selecting the stations, one of: "+strings.Join(generate.ProfileNames(), ", "))
+	resultsFile := flags.String("results", "", "write the exact results of the data in the format of the challenge to the `file`")
+	resultsJSONFile := flags.String("results-json", "", "write the exact results of the data as JSON to the `file`")
+	stationsFile := flags.String("stations", generate.DefaultStationsFile, "the `file` containing the weather stations")
+	err := flags.Parse(args)
+	if err != nil {
+		return 1
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return 1
+	}
+
+	// Underscores are allowed, like `1_000_000_000`. Not base 0, as that
+	// would read `010` as octal.
+	rows, err := strconv.ParseInt(strings.ReplaceAll(*rowsStr, "_", ""), 10, 64)
+	if err != nil || rows <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: --rows must be a positive integer, not '%s'\n", *rowsStr)
+		return 1
+	}
+
+	distribution, err := generate.ParseDistribution(*distributionName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 1
+	}
+	profile, err := generate.ParseProfile(*profileName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 1
+	}
+	if *stdDev <= 0 {
+		fmt.Fprintln(os.Stderr, "Error: --stddev must be positive")
+		return 1
+	}
+
+	stations, err := generate.ReadStationsFile(*stationsFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+
+	opts := generate.Options{
+		Rows:         rows,
+		Seed:         *seed,
+		Workers:      *workers,
+		Stations:     stations,
+		Distribution: distribution,
+		StdDev:       *stdDev,
+		Profile:      profile,
+	}
+
+	start := time.Now()
+	var results onebrc.Results
+	if *outFile == "-" {
+		results, err = generate.Generate(os.Stdout, opts)
+	} else {
+		results, err = generate.GenerateFile(*outFile, opts)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+
+	for _, output := range []struct {
+		fileName string
+		format   onebrc.Format
+	}{{*resultsFile, onebrc.Format1BRC}, {*resultsJSONFile, onebrc.FormatJSON}} {
+		if output.fileName == "" {
+			continue
+		}
+		err = writeResultsFile(output.fileName, results, output.format)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+	}
+
+	if *outFile != "-" {
+		fmt.Fprintf(os.Stderr, "Wrote %d rows to '%s' in %s\n", rows, *outFile, time.Since(start).Round(time.Millisecond))
+	}
+	return 0
+}
+
+// writeResultsFile writes `results` in the format `format` to the file
+// `fileName`.
+func writeResultsFile(fileName string, results onebrc.Results, format onebrc.Format) error {
+	file, err := os.Create(fileName)
+	if err != nil {
+		return fmt.Errorf("error creating results file '%s': %w", fileName, err)
+	}
+	err = results.Write(file, format)
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("error writing results file '%s': %w", fileName, err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     cmd/onebrc/main.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+// onebrc runs all Go versions of the one billion row challenge.
+//
+// Usage:
+//
+//	onebrc [command] [options] [arguments]
+//
+// Without a command, `run` is used, so `onebrc measurements.txt` processes the
+// file using the fastest version and `zcat measurements.txt.gz | onebrc` the
+// data read from stdin.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+type command struct {
+	name        string
+	description string
+	run         func(args []string) int
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{
+			name:        "run",
+			description: "calculate the min, mean and max temperature of each station",
+			run:         runCommand,
+		},
+		{
+			name:        "generate",
+			description: "generate a measurements file",
+			run:         generateCommand,
+		},
+		{
+			name:        "parse-results",
+			description: "parse results in the format of the challenge and convert them",
+			run:         parseResultsCommand,
+		},
+		{
+			name:        "compare",
+			description: "compare two results files station by station",
+			run:         compareCommand,
+		},
+		{
+			name:        "bench",
+			description: "benchmark variants, like hyperfine",
+			run:         benchCommand,
+		},
+		{
+			name:        "variants",
+			description: "list all variants of the solution",
+			run:         variantsCommand,
+		},
+		{
+			name:        "help",
+			description: "print this help",
+			run:         helpCommand,
+		},
+	}
+}
+
+func main() {
+	args := os.Args[1:]
+	if len(args) < 1 {
+		os.Exit(runCommand(args))
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			os.Exit(cmd.run(args[1:]))
+		}
+	}
+	if args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+		os.Exit(helpCommand(args[1:]))
+	}
+
+	os.Exit(runCommand(args))
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: onebrc [command] [options] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-14s %s\n", cmd.name, cmd.description)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Without a command, `run` is used. Use `onebrc <command> -h` for the options of a command.")
+}
+
+func helpCommand(_ []string) int {
+	usage(os.Stdout)
+	return 0
+}
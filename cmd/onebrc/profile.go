@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     cmd/onebrc/profile.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+)
+
+// profileFlags are the names of the profile files to write, empty if the
+// profile isn't wanted.
+type profileFlags struct {
+	cpuProfile   string
+	memProfile   string
+	blockProfile string
+	mutexProfile string
+	trace        string
+}
+
+// addProfileFlags adds the profiling flags to `flags`.
+func addProfileFlags(flags *flag.FlagSet) *profileFlags {
+	p := &profileFlags{}
+	flags.StringVar(&p.cpuProfile, "cpuprofile", "", "write a CPU profile to the `file`")
+	flags.StringVar(&p.memProfile, "memprofile", "", "write a memory allocation profile to the `file`")
+	flags.StringVar(&p.blockProfile, "blockprofile", "", "write a goroutine blocking profile to the `file`")
+	flags.StringVar(&p.mutexProfile, "mutexprofile", "", "write a mutex contention profile to the `file`")
+	flags.StringVar(&p.trace, "trace", "", "write an execution trace to the `file`")
+	return p
+}
+
+// start starts the CPU profile and the trace and enables the profiling of
+// blocking goroutines and mutexes. The returned function stops them and writes
+// the memory, block and mutex profiles, it must be called even if start
+// returns an error.
+func (p *profileFlags) start() (func() error, error) {
+	var stops []func() error
+	stop := func() error {
+		var errs []error
+		for idx := len(stops) - 1; idx >= 0; idx-- {
+			errs = append(errs, stops[idx]())
+		}
+		return errors.Join(errs...)
+	}
+
+	if p.cpuProfile != "" {
+		file, err := os.Create(p.cpuProfile)
+		if err != nil {
+			return stop, fmt.Errorf("error creating CPU profile: %w", err)
+		}
+		err = pprof.StartCPUProfile(file)
+		if err != nil {
+			file.Close()
+			return stop, fmt.Errorf("error starting CPU profile: %w", err)
+		}
+		stops = append(stops, func() error {
+			pprof.StopCPUProfile()
+			return closeProfile(file, "CPU profile")
+		})
+	}
+
+	if p.trace != "" {
+		file, err := os.Create(p.trace)
+		if err != nil {
+			return stop, fmt.Errorf("error creating trace file: %w", err)
+		}
+		err = trace.Start(file)
+		if err != nil {
+			file.Close()
+			return stop, fmt.Errorf("error starting trace: %w", err)
+		}
+		stops = append(stops, func() error {
+			trace.Stop()
+			return closeProfile(file, "trace")
+		})
+	}
+
+	if p.blockProfile != "" {
+		runtime.SetBlockProfileRate(1)
+		stops = append(stops, func() error {
+			runtime.SetBlockProfileRate(0)
+			return writeProfile("block", p.blockProfile)
+		})
+	}
+
+	if p.mutexProfile != "" {
+		runtime.SetMutexProfileFraction(1)
+		stops = append(stops, func() error {
+			runtime.SetMutexProfileFraction(0)
+			return writeProfile("mutex", p.mutexProfile)
+		})
+	}
+
+	if p.memProfile != "" {
+		stops = append(stops, func() error {
+			// Get up-to-date statistics, like `go test -memprofile`.
+			runtime.GC()
+			return writeProfile("allocs", p.memProfile)
+		})
+	}
+
+	return stop, nil
+}
+
+// writeProfile writes the profile `name` of runtime/pprof to the file
+// `fileName`.
+func writeProfile(name string, fileName string) error {
+	file, err := os.Create(fileName)
+	if err != nil {
+		return fmt.Errorf("error creating %s profile: %w", name, err)
+	}
+	err = pprof.Lookup(name).WriteTo(file, 0)
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("error writing %s profile: %w", name, err)
+	}
+	return closeProfile(file, name+" profile")
+}
+
+// closeProfile closes the file of the profile `name`.
+func closeProfile(file *os.File, name string) error {
+	err := file.Close()
+	if err != nil {
+		return fmt.Errorf("error writing %s: %w", name, err)
+	}
+	return nil
+}
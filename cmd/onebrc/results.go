@@ -0,0 +1,136 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     cmd/onebrc/results.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+)
+
+func parseResultsCommand(args []string) int {
+	flags := flag.NewFlagSet("parse-results", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: onebrc parse-results [options] [results file]")
+		fmt.Fprintln(flags.Output())
+		fmt.Fprintln(flags.Output(), "Parses results in the format of the challenge, `{Abha=-23.0/18.0/59.2, ...}`,")
+		fmt.Fprintln(flags.Output(), "and writes them in another format.")
+		fmt.Fprintln(flags.Output(), "Without a results file or if it is `-`, the results are read from stdin.")
+		fmt.Fprintln(flags.Output())
+		fmt.Fprintln(flags.Output(), "Options:")
+		flags.PrintDefaults()
+	}
+	formatName := flags.String("format", onebrc.FormatJSON.String(),
+		"the output `format`, one of: "+strings.Join(onebrc.FormatNames(), ", "))
+	err := flags.Parse(args)
+	if err != nil {
+		return 1
+	}
+
+	format, err := onebrc.ParseFormat(*formatName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 1
+	}
+
+	fileName := flags.Arg(0)
+	input := os.Stdin
+	if fileName != "" && fileName != "-" {
+		input, err = os.Open(fileName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+		defer input.Close()
+	}
+
+	summaries, err := onebrc.ParseResults(input)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+
+	err = summaries.Write(os.Stdout, format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing the results: %s\n", err)
+		return 2
+	}
+	return 0
+}
+
+func compareCommand(args []string) int {
+	flags := flag.NewFlagSet("compare", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: onebrc compare [options] <expected results file> <actual results file>")
+		fmt.Fprintln(flags.Output())
+		fmt.Fprintln(flags.Output(), "Compares two results files station by station, in any format written by `onebrc run`.")
+		fmt.Fprintln(flags.Output(), "Exits with 3 if the results differ.")
+		fmt.Fprintln(flags.Output())
+		fmt.Fprintln(flags.Output(), "Options:")
+		flags.PrintDefaults()
+	}
+	tolerance := flags.Float64("tolerance", 0, "the maximum difference of the mean temperatures in `degrees`")
+	err := flags.Parse(args)
+	if err != nil {
+		return 1
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 1
+	}
+	if *tolerance < 0 {
+		fmt.Fprintln(os.Stderr, "Error: --tolerance must not be negative")
+		return 1
+	}
+
+	expected, err := readResultsFile(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	actual, err := readResultsFile(flags.Arg(1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+
+	differences := onebrc.Compare(expected, actual, *tolerance)
+	if len(differences) == 0 {
+		fmt.Printf("Results are equal, %d stations.\n", len(expected))
+		return 0
+	}
+
+	var counts [3]int
+	for _, difference := range differences {
+		fmt.Println(difference)
+		counts[difference.Kind]++
+	}
+	fmt.Printf("%d differences: %d missing stations, %d extra stations, %d different values\n",
+		len(differences), counts[onebrc.DifferenceMissing], counts[onebrc.DifferenceExtra], counts[onebrc.DifferenceField])
+	return 3
+}
+
+// readResultsFile reads the results file `fileName` in any format.
+func readResultsFile(fileName string) (onebrc.Summaries, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	summaries, err := onebrc.ReadResults(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading results file '%s': %w", fileName, err)
+	}
+	return summaries, nil
+}
@@ -0,0 +1,259 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     cmd/onebrc/run.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/bits"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+	"github.com/Release-Candidate/1-billion-row-challenge/variants"
+)
+
+func runCommand(args []string) int {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: onebrc run [options] [data file]")
+		fmt.Fprintln(flags.Output())
+		fmt.Fprintln(flags.Output(), "Without a data file or if it is `-`, the data is read from stdin.")
+		fmt.Fprintln(flags.Output(), "Gzip and bzip2 compressed data is decompressed.")
+		fmt.Fprintln(flags.Output(), "The profiles and the trace are only written if their flag is given, for every variant.")
+		fmt.Fprintln(flags.Output())
+		fmt.Fprintln(flags.Output(), "Options:")
+		flags.PrintDefaults()
+	}
+	variantName := flags.String("variant", variants.Default, "the `name` of the variant to run, see `onebrc variants`")
+	strict := flags.Bool("strict", false, "check every line against the rules of the challenge and stop at the first invalid one")
+	lenient := flags.Bool("lenient", false, "check every line against the rules of the challenge and skip invalid ones")
+	rejectsFile := flags.String("rejects", "", "write the lines skipped by --lenient to the `file`")
+	formatName := flags.String("format", onebrc.Format1BRC.String(),
+		"the output `format`, one of: "+strings.Join(onebrc.FormatNames(), ", "))
+	tables := addTableFlags(flags)
+	stats := flags.Bool("stats", false,
+		"print the collisions, probe lengths, longest chains and fill ratios of the hash tables to stderr")
+	profiling := addProfileFlags(flags)
+	err := flags.Parse(args)
+	if err != nil {
+		return 1
+	}
+
+	fileName := flags.Arg(0)
+	if fileName == "" {
+		fileName = "-"
+	}
+	if fileName == "-" && isTerminal(os.Stdin) {
+		fmt.Fprintln(os.Stderr, "Error: no data file to process given and stdin is a terminal! Exiting.")
+		return 1
+	}
+
+	variant, err := variants.Get(*variantName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 1
+	}
+
+	format, err := onebrc.ParseFormat(*formatName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 1
+	}
+
+	opts := onebrc.Options{}
+	err = tables.setOptions(flags, &opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 1
+	}
+	if *stats {
+		opts.TableStats = true
+		opts.Report = &onebrc.Report{}
+	}
+	switch {
+	case *strict && *lenient:
+		fmt.Fprintln(os.Stderr, "Error: --strict and --lenient can't be used together")
+		return 1
+	case *strict:
+		opts.Mode = onebrc.ModeStrict
+	case *lenient:
+		opts.Mode = onebrc.ModeLenient
+		if opts.Report == nil {
+			opts.Report = &onebrc.Report{}
+		}
+	}
+
+	if *rejectsFile != "" {
+		if !*lenient {
+			fmt.Fprintln(os.Stderr, "Error: --rejects needs --lenient")
+			return 1
+		}
+		rejects, err := os.Create(*rejectsFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating the rejects file: %s\n", err)
+			return 2
+		}
+		defer rejects.Close()
+		opts.RejectWriter = rejects
+	}
+
+	stopProfiling, err := profiling.start()
+	if err != nil {
+		stopProfiling()
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	results, err := runVariant(variant, fileName, opts)
+	profilingErr := stopProfiling()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	if profilingErr != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", profilingErr)
+		return 2
+	}
+
+	if opts.Mode == onebrc.ModeLenient {
+		printRejects(opts.Report)
+		if opts.Report.Stations > onebrc.MaxStations {
+			fmt.Fprintf(os.Stderr, "Warning: %d different stations, the rules allow at most %d\n",
+				opts.Report.Stations, onebrc.MaxStations)
+		}
+	}
+
+	if opts.TableStats {
+		printTableStats(opts.Report)
+	}
+
+	err = results.Write(os.Stdout, format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing the results: %s\n", err)
+		return 2
+	}
+	return 0
+}
+
+// printRejects prints the number of lines skipped by ModeLenient to stderr.
+func printRejects(report *onebrc.Report) {
+	total := report.Rejects.Total()
+	if total == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Skipped %d of %d lines:\n", total, report.Lines)
+	for _, reason := range onebrc.Reasons() {
+		count := report.Rejects.Count(reason)
+		if count > 0 {
+			fmt.Fprintf(os.Stderr, "  %10d %s\n", count, reason)
+		}
+	}
+}
+
+// printTableStats prints the statistics of the hash tables of the workers and
+// the merged one to stderr, and the probe length histograms of both.
+func printTableStats(report *onebrc.Report) {
+	fmt.Fprintf(os.Stderr, "%-10s %9s %9s %7s %9s %9s %12s %9s %9s\n",
+		"table", "stations", "slots", "fill", "overflow", "colliding", "collisions", "max probe", "max chain")
+	printStats := func(name string, stats onebrc.TableStats) {
+		fmt.Fprintf(os.Stderr, "%-10s %9d %9d %6.2f%% %9d %9d %12d %9d %9d\n",
+			name, stats.Stations, stats.Slots, 100*stats.FillRatio(), stats.Overflow,
+			stats.Colliding, stats.Collisions, stats.MaxProbe(), stats.MaxChain)
+	}
+	var collisions uint64
+	for idx, stats := range report.WorkerTables {
+		printStats(fmt.Sprintf("worker %d", idx+1), stats)
+		collisions += stats.Collisions
+	}
+	printStats("merged", report.MergedTable)
+	fmt.Fprintf(os.Stderr, "Collisions of all workers: %d\n", collisions)
+
+	workers := probeBuckets(onebrc.SumProbeLengths(report.WorkerTables))
+	merged := probeBuckets(report.MergedTable.ProbeLengths)
+	fmt.Fprintf(os.Stderr, "%-10s %9s %9s\n", "probe", "workers", "merged")
+	for bucket := range max(len(workers), len(merged)) {
+		var numWorkers, numMerged int
+		if bucket < len(workers) {
+			numWorkers = workers[bucket]
+		}
+		if bucket < len(merged) {
+			numMerged = merged[bucket]
+		}
+		fmt.Fprintf(os.Stderr, "%-10s %9d %9d\n", probeBucketName(bucket), numWorkers, numMerged)
+	}
+}
+
+// probeBuckets sums the histogram of probe lengths `lengths` into buckets of
+// powers of two: 0, 1, 2-3, 4-7, ... Else data like `fnv-collide` would print
+// a line for each of its 10,000 probe lengths.
+func probeBuckets(lengths []int) []int {
+	if len(lengths) == 0 {
+		return nil
+	}
+	buckets := make([]int, bits.Len(uint(len(lengths)-1))+1)
+	for probe, count := range lengths {
+		buckets[bits.Len(uint(probe))] += count
+	}
+	return buckets
+}
+
+// probeBucketName returns the range of probe lengths of the bucket with index
+// `bucket` of probeBuckets.
+func probeBucketName(bucket int) string {
+	if bucket < 2 {
+		return strconv.Itoa(bucket)
+	}
+	return fmt.Sprintf("%d-%d", 1<<(bucket-1), 1<<bucket-1)
+}
+
+// runVariant runs `variant` using the options `opts`. The options and reading
+// from stdin, if `fileName` is `-`, are only supported by variants
+// implementing variants.OptionsVariant.
+func runVariant(variant variants.Variant, fileName string, opts onebrc.Options) (onebrc.Results, error) {
+	if optsVariant, ok := variant.(variants.OptionsVariant); ok {
+		if fileName == "-" {
+			return optsVariant.RunReader(os.Stdin, opts)
+		}
+		return optsVariant.RunOptions(fileName, opts)
+	}
+	if fileName == "-" {
+		return nil, fmt.Errorf("variant '%s' can't read from stdin, use variant '%s'", variant.Name(), variants.Default)
+	}
+	if opts != (onebrc.Options{}) {
+		return nil, fmt.Errorf("variant '%s' does not support these options, use variant '%s'", variant.Name(), variants.Default)
+	}
+	return variant.Run(fileName)
+}
+
+// isTerminal returns true, if `file` is a terminal.
+func isTerminal(file *os.File) bool {
+	stat, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
+func variantsCommand(args []string) int {
+	flags := flag.NewFlagSet("variants", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: onebrc variants")
+	}
+	err := flags.Parse(args)
+	if err != nil {
+		return 1
+	}
+
+	for _, variant := range variants.All() {
+		fmt.Printf("%-28s %s\n", variant.Name(), variant.Description())
+	}
+	return 0
+}
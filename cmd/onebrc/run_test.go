@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     cmd/onebrc/run_test.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestProbeBuckets(t *testing.T) {
+	tests := []struct {
+		lengths []int
+		want    []int
+	}{
+		{nil, nil},
+		{[]int{5}, []int{5}},
+		{[]int{5, 3}, []int{5, 3}},
+		{[]int{5, 3, 2}, []int{5, 3, 2}},
+		{[]int{5, 3, 2, 1, 1}, []int{5, 3, 3, 1}},
+		{[]int{1, 1, 1, 1, 1, 1, 1, 1, 1}, []int{1, 1, 2, 4, 1}},
+	}
+	for _, test := range tests {
+		got := probeBuckets(test.lengths)
+		if !slices.Equal(got, test.want) {
+			t.Errorf("probeBuckets(%v) = %v, want %v", test.lengths, got, test.want)
+		}
+	}
+
+	names := []string{"0", "1", "2-3", "4-7", "8-15"}
+	for bucket, want := range names {
+		if got := probeBucketName(bucket); got != want {
+			t.Errorf("probeBucketName(%d) = %s, want %s", bucket, got, want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     cmd/onebrc/table.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+)
+
+// tableFlags are the options of the hash tables of the station names.
+type tableFlags struct {
+	loadFactor     float64
+	hasherName     string
+	maxProbe       int
+	floodResistant bool
+}
+
+// addTableFlags adds the flags of the hash tables to `flags`.
+func addTableFlags(flags *flag.FlagSet) *tableFlags {
+	t := &tableFlags{}
+	flags.Float64Var(&t.loadFactor, "load-factor", onebrc.DefaultLoadFactor,
+		"the maximum `ratio` of used slots of the hash tables of the station names, before they grow")
+	flags.StringVar(&t.hasherName, "hash", onebrc.DefaultHasher,
+		"the hash `function` of the station names, one of: "+strings.Join(onebrc.HasherNames(), ", "))
+	flags.IntVar(&t.maxProbe, "max-probe", 0,
+		"the maximum `number` of slots searched by a lookup in the hash tables before using a Go map, 0 is no limit")
+	flags.BoolVar(&t.floodResistant, "flood-resistant", false,
+		fmt.Sprintf("resist hash flooding: use maphash with a random seed, unless --hash is given, and a --max-probe of %d, unless given",
+			onebrc.DefaultMaxProbe))
+	return t
+}
+
+// setOptions sets the hash table options of `opts`, `flags` are the parsed
+// flags. --hash and --max-probe win over the defaults of --flood-resistant if
+// they are given, even with their default values.
+func (t *tableFlags) setOptions(flags *flag.FlagSet, opts *onebrc.Options) error {
+	if t.loadFactor <= 0 || t.loadFactor > 1 {
+		return errors.New("--load-factor must be greater than 0 and at most 1")
+	}
+	if t.maxProbe < 0 {
+		return errors.New("--max-probe must not be negative")
+	}
+	given := map[string]bool{}
+	flags.Visit(func(f *flag.Flag) {
+		given[f.Name] = true
+	})
+
+	// Only set if not the default, other variants don't support the options.
+	if t.loadFactor != onebrc.DefaultLoadFactor {
+		opts.LoadFactor = t.loadFactor
+	}
+	if t.hasherName != onebrc.DefaultHasher || (t.floodResistant && given["hash"]) {
+		hasher, err := onebrc.ParseHasher(t.hasherName)
+		if err != nil {
+			return err
+		}
+		opts.Hasher = hasher
+	}
+	opts.MaxProbe = t.maxProbe
+	if t.floodResistant && given["max-probe"] && t.maxProbe == 0 {
+		// No limit, instead of the default of FloodResistant.
+		opts.MaxProbe = -1
+	}
+	opts.FloodResistant = t.floodResistant
+	return nil
+}
@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     cmd/onebrc/table_test.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/generate"
+	"github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+)
+
+// parseTableFlags returns the options of the hash tables set by `args`.
+func parseTableFlags(t *testing.T, args string) onebrc.Options {
+	t.Helper()
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	tables := addTableFlags(flags)
+	err := flags.Parse(strings.Fields(args))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := onebrc.Options{}
+	err = tables.setOptions(flags, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return opts
+}
+
+// TestTableFlags checks that --hash and --max-probe win over the defaults of
+// --flood-resistant, also with their default values.
+func TestTableFlags(t *testing.T) {
+	for _, tc := range []struct {
+		args     string
+		hasher   string
+		maxProbe int
+	}{
+		{"", "", 0},
+		{"--hash=fnv", "", 0},
+		{"--hash=maphash --max-probe=4", "maphash", 4},
+		{"--flood-resistant", "", 0},
+		{"--flood-resistant --hash=fnv", "fnv", 0},
+		{"--flood-resistant --max-probe=0", "", -1},
+		{"--flood-resistant --max-probe=4", "", 4},
+		{"--flood-resistant --hash=fnv --max-probe=0", "fnv", -1},
+	} {
+		opts := parseTableFlags(t, tc.args)
+		hasher := ""
+		if opts.Hasher != nil {
+			hasher = opts.Hasher.Name()
+		}
+		if hasher != tc.hasher || opts.MaxProbe != tc.maxProbe {
+			t.Errorf("%q: got hash %q, max probe %d, want %q, %d", tc.args, hasher, opts.MaxProbe, tc.hasher, tc.maxProbe)
+		}
+	}
+
+	// The statistics of the tables show if FNV-1a is used, with a maximum
+	// probe length or without.
+	stations, err := generate.ReadStationsFile(filepath.Join("..", "..", generate.DefaultStationsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var content bytes.Buffer
+	_, err = generate.Generate(&content, generate.Options{
+		Rows: 20_000, Seed: 1, Stations: stations, Profile: generate.ProfileFNVCollide,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		args      string
+		overflow  bool
+		longProbe bool
+	}{
+		{"--flood-resistant --hash=fnv", true, false},
+		{"--flood-resistant --hash=fnv --max-probe=0", false, true},
+	} {
+		opts := parseTableFlags(t, tc.args)
+		opts.TableStats = true
+		opts.Report = &onebrc.Report{}
+		_, err := onebrc.AggregateBytes(content.Bytes(), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stats := opts.Report.MergedTable
+		if (stats.Overflow > 0) != tc.overflow || (stats.MaxProbe() > onebrc.DefaultMaxProbe) != tc.longProbe {
+			t.Errorf("%q: got %d stations in the Go map and a maximum probe length of %d", tc.args, stats.Overflow, stats.MaxProbe())
+		}
+	}
+}
@@ -0,0 +1,330 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     generate/generate.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+// Package generate generates measurement files like create_measurements.py,
+// but in parallel and reproducible: the same seed always generates the same
+// data, regardless of the number of workers.
+//
+// The lines are generated in blocks, each block uses its own random number
+// generator, which is seeded by the seed and the index of the block.
+package generate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+)
+
+const (
+	// The number of lines of a block.
+	blockRows = 64 * 1024
+	// The number of stations chosen from the stations file, like
+	// create_measurements.py does. As these are chosen with replacement,
+	// there are less different stations.
+	numChosenStations = 10_000
+	// The temperatures in tenths of a degree.
+	minTemperature = -999
+	maxTemperature = 999
+	// The standard deviation of the Java CreateMeasurements.
+	defaultStdDev = 10.0
+)
+
+// Distribution is the distribution of the temperatures of a station.
+type Distribution int
+
+const (
+	// DistributionUniform are temperatures uniformly distributed between
+	// -99.9 and 99.9, like create_measurements.py.
+	DistributionUniform Distribution = iota
+	// DistributionGaussian are temperatures normally distributed around the
+	// mean temperature of the station in the stations file, like the Java
+	// CreateMeasurements. Temperatures outside of -99.9 and 99.9 are clamped.
+	DistributionGaussian
+)
+
+var distributionNames = []string{"uniform", "gaussian"}
+
+func (d Distribution) String() string {
+	if d < 0 || int(d) >= len(distributionNames) {
+		return fmt.Sprintf("unknown distribution %d", int(d))
+	}
+	return distributionNames[d]
+}
+
+// DistributionNames returns the names of all distributions.
+func DistributionNames() []string {
+	return append([]string(nil), distributionNames...)
+}
+
+// ParseDistribution returns the distribution with the name `name`, like
+// `gaussian`.
+func ParseDistribution(name string) (Distribution, error) {
+	for idx, distributionName := range distributionNames {
+		if distributionName == name {
+			return Distribution(idx), nil
+		}
+	}
+	return DistributionUniform, fmt.Errorf("unknown distribution '%s', valid distributions are: %s",
+		name, strings.Join(distributionNames, ", "))
+}
+
+// Options configures Generate.
+type Options struct {
+	// Rows is the number of lines to generate.
+	Rows int64
+	// Seed is the seed of the random number generators.
+	Seed uint64
+	// Workers is the number of goroutines generating the lines. The default
+	// is runtime.NumCPU(). The data does not depend on the number of workers.
+	Workers int
+	// Stations are the weather stations to choose from, see ReadStations.
+	Stations []Station
+	// Distribution is the distribution of the temperatures. The default is
+	// DistributionUniform.
+	Distribution Distribution
+	// StdDev is the standard deviation in degrees of DistributionGaussian.
+	// The default is 10.
+	StdDev float64
+	// Profile selects the stations. The default is ProfileDefault.
+	Profile Profile
+}
+
+func (o Options) numWorkers() int {
+	if o.Workers > 0 {
+		return o.Workers
+	}
+	return runtime.NumCPU()
+}
+
+func (o Options) stdDev() float64 {
+	if o.StdDev > 0 {
+		return o.StdDev
+	}
+	return defaultStdDev
+}
+
+// GenerateFile writes the generated data to the file `fileName`, see Generate.
+func GenerateFile(fileName string, opts Options) (results onebrc.Results, err error) {
+	file, err := os.Create(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("error creating file '%s': %w", fileName, err)
+	}
+	defer func() {
+		closeErr := file.Close()
+		if closeErr != nil && err == nil {
+			err = fmt.Errorf("error closing file '%s': %w", fileName, closeErr)
+		}
+	}()
+
+	return Generate(file, opts)
+}
+
+// Generate writes `opts.Rows` lines of measurements to `w`. The stations are
+// chosen out of `opts.Stations` according to `opts.Profile`, the temperatures
+// are distributed according to `opts.Distribution`.
+// Returns the exact results of the generated data, which any solution must
+// produce.
+func Generate(w io.Writer, opts Options) (onebrc.Results, error) {
+	if opts.Rows < 0 {
+		return nil, fmt.Errorf("the number of rows must not be negative, is %d", opts.Rows)
+	}
+	if len(opts.Stations) == 0 {
+		return nil, errors.New("no weather stations to choose from")
+	}
+
+	stations, err := chooseStations(opts.Stations, opts.Profile, opts.Seed)
+	if err != nil {
+		return nil, err
+	}
+	gen := generator{
+		stations:     stations,
+		seed:         opts.Seed,
+		distribution: opts.Distribution,
+		stdDev:       opts.stdDev(),
+		zipf:         opts.Profile == ProfileZipf,
+	}
+	numBlocks := (opts.Rows + blockRows - 1) / blockRows
+	numWorkers := opts.numWorkers()
+
+	// The channels of the blocks in order, this also limits the number of
+	// blocks in memory.
+	blocks := make(chan chan block, numWorkers)
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		defer close(blocks)
+		for blockIdx := int64(0); blockIdx < numBlocks; blockIdx++ {
+			// non-blocking channel
+			channel := make(chan block, 1)
+			select {
+			case blocks <- channel:
+			case <-done:
+				return
+			}
+			rows := min(blockRows, opts.Rows-blockIdx*blockRows)
+			go func() {
+				channel <- gen.block(blockIdx, int(rows))
+			}()
+		}
+	}()
+
+	sum := newStationStats(len(stations))
+	for channel := range blocks {
+		block := <-channel
+		_, err = w.Write(block.data)
+		if err != nil {
+			return nil, fmt.Errorf("error writing data: %w", err)
+		}
+		sum.add(block.stats)
+	}
+	return sum.results(stations), nil
+}
+
+// block is a block of generated lines and the statistics of its stations.
+type block struct {
+	data  []byte
+	stats stationStats
+}
+
+// stationStats are the statistics of the stations, indexed like the stations
+// of the generator. The temperatures are in tenths of a degree.
+type stationStats struct {
+	min   []int
+	max   []int
+	sum   []int
+	count []uint
+}
+
+func newStationStats(numStations int) stationStats {
+	stats := stationStats{
+		min:   make([]int, numStations),
+		max:   make([]int, numStations),
+		sum:   make([]int, numStations),
+		count: make([]uint, numStations),
+	}
+	for idx := range numStations {
+		stats.min[idx] = maxTemperature
+		stats.max[idx] = minTemperature
+	}
+	return stats
+}
+
+func (s stationStats) addTemperature(idx int, temperature int) {
+	s.min[idx] = min(s.min[idx], temperature)
+	s.max[idx] = max(s.max[idx], temperature)
+	s.sum[idx] += temperature
+	s.count[idx]++
+}
+
+func (s stationStats) add(other stationStats) {
+	for idx := range s.count {
+		s.min[idx] = min(s.min[idx], other.min[idx])
+		s.max[idx] = max(s.max[idx], other.max[idx])
+		s.sum[idx] += other.sum[idx]
+		s.count[idx] += other.count[idx]
+	}
+}
+
+// results returns the results of the stations `stations`. The same station
+// may be more than once in `stations`.
+func (s stationStats) results(stations []Station) onebrc.Results {
+	byName := make(map[string]onebrc.Station)
+	for idx, station := range stations {
+		if s.count[idx] == 0 {
+			continue
+		}
+		result, ok := byName[station.Name]
+		if !ok {
+			result = onebrc.Station{Name: station.Name, Min: maxTemperature, Max: minTemperature}
+		}
+		result.Min = min(result.Min, s.min[idx])
+		result.Max = max(result.Max, s.max[idx])
+		result.Sum += s.sum[idx]
+		result.Count += s.count[idx]
+		byName[station.Name] = result
+	}
+
+	results := make(onebrc.Results, 0, len(byName))
+	for _, result := range byName {
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
+
+// generator generates the blocks of lines.
+type generator struct {
+	stations     []Station
+	seed         uint64
+	distribution Distribution
+	stdDev       float64
+	// Choose the stations using a Zipf distribution.
+	zipf bool
+}
+
+// block returns the `rows` lines of the block with index `blockIdx`,
+// generated using the random number generator with stream `blockIdx + 1`.
+func (g generator) block(blockIdx int64, rows int) block {
+	rng := rand.New(rand.NewPCG(g.seed, uint64(blockIdx)+1))
+	// About 16 bytes per line.
+	buffer := make([]byte, 0, rows*16)
+	var zipf *rand.Zipf
+	if g.zipf {
+		zipf = rand.NewZipf(rng, zipfS, zipfV, uint64(len(g.stations)-1))
+	}
+	stats := newStationStats(len(g.stations))
+	for range rows {
+		var stationIdx int
+		if zipf != nil {
+			stationIdx = int(zipf.Uint64())
+		} else {
+			stationIdx = rng.IntN(len(g.stations))
+		}
+		station := g.stations[stationIdx]
+		temperature := g.temperature(rng, station)
+		stats.addTemperature(stationIdx, temperature)
+		buffer = append(buffer, station.Name...)
+		buffer = append(buffer, ';')
+		buffer = appendTenths(buffer, temperature)
+		buffer = append(buffer, '\n')
+	}
+	return block{data: buffer, stats: stats}
+}
+
+// temperature returns a random temperature of `station` in tenths of a degree.
+func (g generator) temperature(rng *rand.Rand, station Station) int {
+	if g.distribution == DistributionGaussian {
+		temperature := int(math.Round((rng.NormFloat64()*g.stdDev + station.Mean) * 10))
+		return min(max(temperature, minTemperature), maxTemperature)
+	}
+	return rng.IntN(maxTemperature-minTemperature+1) + minTemperature
+}
+
+// appendTenths appends the temperature `tenths` in tenths of a degree as
+// degrees with one fractional digit to `buffer`.
+func appendTenths(buffer []byte, tenths int) []byte {
+	if tenths < 0 {
+		buffer = append(buffer, '-')
+		tenths = -tenths
+	}
+	buffer = strconv.AppendInt(buffer, int64(tenths/10), 10)
+	return append(buffer, '.', byte('0'+tenths%10))
+}
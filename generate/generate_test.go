@@ -0,0 +1,237 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     generate/generate_test.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package generate_test
+
+import (
+	"bytes"
+	"math"
+	"sort"
+	"strconv"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/generate"
+	"github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+	"github.com/Release-Candidate/1-billion-row-challenge/reference"
+)
+
+// More than 4 blocks and not a multiple of the block size.
+const testRows = 300_001
+
+// readStations returns the stations of the stations file in the root of the
+// repository.
+func readStations(t *testing.T) []generate.Station {
+	t.Helper()
+	stations, err := generate.ReadStationsFile("../" + generate.DefaultStationsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stations
+}
+
+// generateData returns the generated data and its results.
+func generateData(t *testing.T, opts generate.Options) ([]byte, onebrc.Results) {
+	t.Helper()
+	var buffer bytes.Buffer
+	results, err := generate.Generate(&buffer, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes(), results
+}
+
+// TestGenerateWorkers checks that the same seed generates the same data with
+// any number of workers, and another seed different data.
+func TestGenerateWorkers(t *testing.T) {
+	stations := readStations(t)
+	for _, profile := range []generate.Profile{generate.ProfileDefault, generate.ProfileZipf} {
+		opts := generate.Options{Rows: testRows, Seed: 42, Workers: 1, Stations: stations, Profile: profile}
+		want, _ := generateData(t, opts)
+		if lines := bytes.Count(want, []byte("\n")); lines != testRows {
+			t.Errorf("profile %s: got %d lines, want %d", profile, lines, testRows)
+		}
+
+		for _, workers := range []int{2, 3, 8, 0} {
+			opts.Workers = workers
+			data, _ := generateData(t, opts)
+			if !bytes.Equal(data, want) {
+				t.Errorf("profile %s: %d workers generated other data than 1 worker", profile, workers)
+			}
+		}
+
+		opts.Seed = 43
+		data, _ := generateData(t, opts)
+		if bytes.Equal(data, want) {
+			t.Errorf("profile %s: seeds 42 and 43 generated the same data", profile)
+		}
+	}
+}
+
+// parseTemperatures returns the temperatures of all lines of `data` in
+// degrees.
+func parseTemperatures(t *testing.T, data []byte) []float64 {
+	t.Helper()
+	temperatures := make([]float64, 0, testRows)
+	for _, line := range bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n")) {
+		semiColonIdx := bytes.LastIndexByte(line, ';')
+		temperature, err := strconv.ParseFloat(string(line[semiColonIdx+1:]), 64)
+		if err != nil {
+			t.Fatalf("line %q: %v", line, err)
+		}
+		temperatures = append(temperatures, temperature)
+	}
+	return temperatures
+}
+
+// TestGaussianClamp checks that gaussian temperatures with a large standard
+// deviation are clamped to [-99.9, 99.9].
+func TestGaussianClamp(t *testing.T) {
+	data, _ := generateData(t, generate.Options{
+		Rows: testRows, Seed: 1, Stations: readStations(t), Distribution: generate.DistributionGaussian, StdDev: 1_000,
+	})
+	numMin, numMax := 0, 0
+	for _, temperature := range parseTemperatures(t, data) {
+		switch {
+		case temperature < -99.9 || temperature > 99.9:
+			t.Fatalf("got temperature %g, not in [-99.9, 99.9]", temperature)
+		case temperature == -99.9:
+			numMin++
+		case temperature == 99.9:
+			numMax++
+		}
+	}
+	// About 46% each.
+	if numMin < testRows/3 || numMax < testRows/3 {
+		t.Errorf("got %d temperatures of -99.9 and %d of 99.9, want more than %d each", numMin, numMax, testRows/3)
+	}
+}
+
+// TestGaussianMean checks that the mean temperatures of the stations are the
+// means of the stations file, with a small standard deviation.
+func TestGaussianMean(t *testing.T) {
+	stations := readStations(t)
+	means := make(map[string]float64, len(stations))
+	for _, station := range stations {
+		means[station.Name] = station.Mean
+	}
+	_, results := generateData(t, generate.Options{
+		Rows: testRows, Seed: 1, Stations: stations, Distribution: generate.DistributionGaussian, StdDev: 0.5,
+	})
+	for _, result := range results {
+		want := min(max(means[result.Name], -99.9), 99.9)
+		if math.Abs(result.MeanTemp()-want) > 0.5 {
+			t.Errorf("station %s: got mean %g, want about %g", result.Name, result.MeanTemp(), want)
+		}
+	}
+}
+
+// fnv16 returns the bucket of `name` in the hash table of processChunk of
+// go_parallel_fnv.go: the lowest 16 bits of the FNV-1a hash of the bytes of
+// the name after the first.
+func fnv16(name string) uint32 {
+	var hash uint32 = 2166136261
+	for idx := 1; idx < len(name); idx++ {
+		hash ^= uint32(name[idx])
+		hash *= 16777619
+	}
+	return hash & 0xffff
+}
+
+// TestProfiles checks the stations of the profiles.
+func TestProfiles(t *testing.T) {
+	stations := readStations(t)
+	for _, tc := range []struct {
+		profile generate.Profile
+		check   func(t *testing.T, results onebrc.Results)
+	}{
+		{generate.Profile10K, func(t *testing.T, results onebrc.Results) {}},
+		{generate.ProfileLongNames, func(t *testing.T, results onebrc.Results) {
+			multiByte := 0
+			for _, result := range results {
+				if len(result.Name) != onebrc.MaxNameLength || !utf8.ValidString(result.Name) {
+					t.Fatalf("name %q: got %d bytes, valid UTF-8 %t, want %d, true",
+						result.Name, len(result.Name), utf8.ValidString(result.Name), onebrc.MaxNameLength)
+				}
+				if utf8.RuneCountInString(result.Name) < len(result.Name) {
+					multiByte++
+				}
+			}
+			if multiByte == 0 {
+				t.Error("no name contains multi-byte characters")
+			}
+		}},
+		{generate.ProfileFNVCollide, func(t *testing.T, results onebrc.Results) {
+			bucket := fnv16(results[0].Name)
+			for _, result := range results {
+				if fnv16(result.Name) != bucket {
+					t.Fatalf("name %q: got bucket %d, want %d", result.Name, fnv16(result.Name), bucket)
+				}
+			}
+		}},
+		{generate.ProfileZipf, func(t *testing.T, results onebrc.Results) {
+			counts := make([]int, 0, len(results))
+			for _, result := range results {
+				counts = append(counts, int(result.Count))
+			}
+			sort.Sort(sort.Reverse(sort.IntSlice(counts)))
+			// About 15% of the lines are the most frequent station, about
+			// half of the lines the 100 most frequent ones.
+			top100 := 0
+			for _, count := range counts[:100] {
+				top100 += count
+			}
+			if counts[0] < testRows/10 || top100 < testRows/3 {
+				t.Errorf("got %d lines of the most frequent station and %d of the 100 most frequent, "+
+					"want more than %d and %d", counts[0], top100, testRows/10, testRows/3)
+			}
+		}},
+	} {
+		t.Run(tc.profile.String(), func(t *testing.T) {
+			_, results := generateData(t, generate.Options{
+				Rows: testRows, Seed: 7, Stations: stations, Profile: tc.profile,
+			})
+			// Most of the stations of the Zipf distribution are rare.
+			if len(results) != 10_000 && tc.profile != generate.ProfileZipf {
+				t.Errorf("got %d different stations, want 10000", len(results))
+			}
+			tc.check(t, results)
+		})
+	}
+}
+
+// TestOracle checks the results returned by Generate against the reference
+// solution of the generated data.
+func TestOracle(t *testing.T) {
+	stations := readStations(t)
+	for _, profile := range []generate.Profile{
+		generate.ProfileDefault, generate.Profile10K, generate.ProfileLongNames,
+		generate.ProfileFNVCollide, generate.ProfileZipf,
+	} {
+		for _, distribution := range []generate.Distribution{
+			generate.DistributionUniform, generate.DistributionGaussian,
+		} {
+			t.Run(profile.String()+"/"+distribution.String(), func(t *testing.T) {
+				data, results := generateData(t, generate.Options{
+					Rows: testRows, Seed: 3, Stations: stations, Profile: profile, Distribution: distribution,
+				})
+				expected, err := reference.Solve(bytes.NewReader(data))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(results) != len(expected) {
+					t.Errorf("got %d stations, want %d", len(results), len(expected))
+				}
+				for _, difference := range onebrc.Compare(expected, results.Summaries(), 0) {
+					t.Error(difference)
+				}
+			})
+		}
+	}
+}
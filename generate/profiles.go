@@ -0,0 +1,201 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     generate/profiles.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package generate
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"unicode/utf8"
+)
+
+// Profile selects the weather stations of the generated data.
+type Profile int
+
+const (
+	// ProfileDefault chooses 10,000 stations with replacement out of the
+	// stations file, like create_measurements.py.
+	ProfileDefault Profile = iota
+	// Profile10K uses 10,000 different stations, the maximum allowed by the
+	// rules of the challenge.
+	Profile10K
+	// ProfileLongNames uses 10,000 different stations with names of exactly
+	// 100 bytes, the maximum allowed, containing multi-byte UTF-8 characters.
+	ProfileLongNames
+	// ProfileFNVCollide uses 10,000 different ASCII station names which all
+	// have the same 16 bit FNV-1a hash, the hash table size of
+	// go_parallel_fnv.go, go_parallel_eq.go and c_parallel.c. Like their
+	// parsers, the hash skips the first byte of the name. The bucket depends
+	// on the seed.
+	ProfileFNVCollide
+	// ProfileZipf uses 10,000 different stations, which occur with a Zipf
+	// distribution, so a few stations are in most of the lines.
+	ProfileZipf
+)
+
+var profileNames = []string{"default", "10k", "longnames", "fnv-collide", "zipf"}
+
+func (p Profile) String() string {
+	if p < 0 || int(p) >= len(profileNames) {
+		return fmt.Sprintf("unknown profile %d", int(p))
+	}
+	return profileNames[p]
+}
+
+// ProfileNames returns the names of all profiles.
+func ProfileNames() []string {
+	return append([]string(nil), profileNames...)
+}
+
+// ParseProfile returns the profile with the name `name`, like `10k`.
+func ParseProfile(name string) (Profile, error) {
+	for idx, profileName := range profileNames {
+		if profileName == name {
+			return Profile(idx), nil
+		}
+	}
+	return ProfileDefault, fmt.Errorf("unknown profile '%s', valid profiles are: %s",
+		name, strings.Join(profileNames, ", "))
+}
+
+const (
+	// The maximum length of a station name in bytes.
+	maxNameLength = 100
+	// The parameters of the Zipf distribution of ProfileZipf.
+	zipfS = 1.1
+	zipfV = 1.0
+	// The FNV-1a hash as used by go_parallel_fnv.go, which uses only the
+	// lowest 16 bits.
+	fnvMask        = (1 << 16) - 1
+	fnvPrime       = 16777619
+	fnvOffsetBasis = 2166136261
+)
+
+// chooseStations returns the stations to generate the data of, chosen out of
+// `stations` using the random number generator with stream 0.
+func chooseStations(stations []Station, profile Profile, seed uint64) ([]Station, error) {
+	rng := rand.New(rand.NewPCG(seed, 0))
+	switch profile {
+	case ProfileDefault:
+		chosen := make([]Station, numChosenStations)
+		for idx := range chosen {
+			chosen[idx] = stations[rng.IntN(len(stations))]
+		}
+		return chosen, nil
+	case Profile10K, ProfileZipf:
+		return distinctStations(stations, rng), nil
+	case ProfileLongNames:
+		return longNameStations(distinctStations(stations, rng)), nil
+	case ProfileFNVCollide:
+		return collidingStations(stations, rng), nil
+	}
+	return nil, fmt.Errorf("unknown profile %d", int(profile))
+}
+
+// distinctStations returns 10,000 different stations out of `stations`. If
+// there are less, stations with generated names are added.
+func distinctStations(stations []Station, rng *rand.Rand) []Station {
+	chosen := make([]Station, 0, numChosenStations)
+	for _, idx := range rng.Perm(len(stations)) {
+		if len(chosen) == numChosenStations {
+			return chosen
+		}
+		chosen = append(chosen, stations[idx])
+	}
+	for idx := len(chosen); idx < numChosenStations; idx++ {
+		chosen = append(chosen, Station{
+			Name: fmt.Sprintf("Station %d", idx),
+			Mean: stations[rng.IntN(len(stations))].Mean,
+		})
+	}
+	return chosen
+}
+
+// longNameStations returns the stations with their names lengthened to
+// exactly 100 bytes. The names start with the index of the station, to be
+// unique, and are filled up with the name itself.
+func longNameStations(stations []Station) []Station {
+	long := make([]Station, len(stations))
+	for idx, station := range stations {
+		name := fmt.Sprintf("%05d %s", idx, station.Name)
+		for len(name) < maxNameLength {
+			name += " " + station.Name
+		}
+		// Don't cut a multi-byte UTF-8 character.
+		name = name[:maxNameLength]
+		for !utf8.ValidString(name) {
+			name = name[:len(name)-1]
+		}
+		name += strings.Repeat(".", maxNameLength-len(name))
+		long[idx] = Station{Name: name, Mean: station.Mean}
+	}
+	return long
+}
+
+// fnvStep is a single step of the FNV-1a hash. The lowest 16 bits only depend
+// on the lowest 16 bits of `hash`.
+func fnvStep(hash uint32, currByte byte) uint32 {
+	return ((hash ^ uint32(currByte)) * fnvPrime) & fnvMask
+}
+
+// The characters of the colliding station names.
+const collideAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// collidingStations returns 10,000 stations with names which all have the
+// same 16 bit FNV-1a hash of the name without its first byte, like the
+// parsers calculate it. The mean temperatures are those of random stations
+// of `stations`.
+// Each name is a random prefix and three bytes: the last byte only changes the
+// lowest 8 bits before the last multiplication, so there are
+// `len(collideAlphabet)` hashes before the last byte that yield the bucket.
+// Trying all combinations of the two bytes before hits about 4 of them.
+func collidingStations(stations []Station, rng *rand.Rand) []Station {
+	bucket := uint32(rng.IntN(fnvMask + 1))
+
+	// lastByte[hash] is the last byte yielding the bucket, or 0.
+	var lastByte [fnvMask + 1]byte
+	for hash := uint32(0); hash <= fnvMask; hash++ {
+		for _, currByte := range []byte(collideAlphabet) {
+			if fnvStep(hash, currByte) == bucket {
+				lastByte[hash] = currByte
+			}
+		}
+	}
+
+	chosen := make([]Station, 0, numChosenStations)
+	seen := make(map[string]bool, numChosenStations)
+	prefix := make([]byte, 8)
+	for len(chosen) < numChosenStations {
+		for idx := range prefix {
+			prefix[idx] = collideAlphabet[rng.IntN(len(collideAlphabet))]
+		}
+		var hash uint32 = fnvOffsetBasis & fnvMask
+		for _, currByte := range prefix[1:] {
+			hash = fnvStep(hash, currByte)
+		}
+
+		for _, first := range []byte(collideAlphabet) {
+			firstHash := fnvStep(hash, first)
+			for _, second := range []byte(collideAlphabet) {
+				last := lastByte[fnvStep(firstHash, second)]
+				if last == 0 {
+					continue
+				}
+				name := string(prefix) + string([]byte{first, second, last})
+				if seen[name] || len(chosen) == numChosenStations {
+					continue
+				}
+				seen[name] = true
+				chosen = append(chosen, Station{Name: name, Mean: stations[rng.IntN(len(stations))].Mean})
+			}
+		}
+	}
+	return chosen
+}
@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     generate/stations.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package generate
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DefaultStationsFile is the list of weather stations used by
+// create_measurements.py.
+const DefaultStationsFile = "weather_stations.csv"
+
+// Station is a weather station of the stations file.
+type Station struct {
+	Name string
+	// Mean is the mean temperature of the station in degrees.
+	Mean float64
+}
+
+// ReadStationsFile reads the weather stations of the file `fileName`, see
+// ReadStations.
+func ReadStationsFile(fileName string) ([]Station, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("error opening stations file '%s': %w", fileName, err)
+	}
+	defer file.Close()
+
+	stations, err := ReadStations(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading stations file '%s': %w", fileName, err)
+	}
+	return stations, nil
+}
+
+// ReadStations reads weather stations in the format of weather_stations.csv,
+// `Tokyo;35.6897`. Lines starting with `#` are comments. Only the first
+// station of the same name is used.
+func ReadStations(r io.Reader) ([]Station, error) {
+	var stations []Station
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, meanStr, found := strings.Cut(line, ";")
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid station in line %d: '%s'", lineNum, line)
+		}
+		mean, err := strconv.ParseFloat(meanStr, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mean temperature in line %d: '%s'", lineNum, meanStr)
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		stations = append(stations, Station{Name: name, Mean: mean})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(stations) == 0 {
+		return nil, fmt.Errorf("no stations found")
+	}
+	return stations, nil
+}
@@ -0,0 +1,3 @@
+module github.com/Release-Candidate/1-billion-row-challenge
+
+go 1.22
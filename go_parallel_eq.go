@@ -9,6 +9,8 @@
 
 // Uses FNV hash algorithm: http://www.isthe.com/chongo/tech/comp/fnv/index.html
 
+//go:build ignore
+
 package main
 
 import (
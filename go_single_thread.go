@@ -7,6 +7,8 @@
 //
 // ==============================================================================
 
+//go:build ignore
+
 package main
 
 import (
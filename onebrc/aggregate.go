@@ -0,0 +1,592 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     onebrc/aggregate.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package onebrc
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"syscall"
+)
+
+// Options configures Aggregate and AggregateBytes. The zero value uses the
+// same settings as go_parallel_eq.go.
+type Options struct {
+	// NumWorkers is the number of chunks the data is split into, each chunk is
+	// processed by its own goroutine. The default is 10 * runtime.NumCPU().
+	// When reading a stream, it is the number of goroutines processing the
+	// blocks of the stream, the default is runtime.NumCPU().
+	NumWorkers int
+	// NumSummers is the number of goroutines summing the results of the
+	// chunks. The default is 2.
+	NumSummers int
+	// BlockSize is the size in bytes of the blocks a stream is split into.
+	// The default is 8 MiB.
+	BlockSize int
+	// Mode selects if and how the lines are checked. The default is ModeFast,
+	// which does not check anything.
+	Mode Mode
+	// RejectWriter, if not nil, gets all lines skipped by ModeLenient, each
+	// followed by a newline.
+	RejectWriter io.Writer
+	// Report, if not nil, is filled with information about the processed
+	// data.
+	Report *Report
+	// LoadFactor is the maximum ratio of used slots of the hash tables of the
+	// station names, before they double their size. The default is
+	// DefaultLoadFactor.
+	LoadFactor float64
+	// Hasher is the hash function of the station names. The default is FNV-1a,
+	// see DefaultHasher.
+	Hasher Hasher
+	// MaxProbe is the maximum number of slots searched by a lookup in the hash
+	// tables of the station names, before looking the station up in a Go map.
+	// The default of 0 is no limit, or DefaultMaxProbe with FloodResistant. A
+	// negative value is always no limit.
+	MaxProbe int
+	// FloodResistant resists hash flooding, data with many station names of
+	// the same hash: the default Hasher is `maphash`, with a random seed for
+	// each run of the program, and the default MaxProbe is DefaultMaxProbe.
+	FloodResistant bool
+	// TableStats collects the statistics of the hash tables of the station
+	// names into Report, which must not be nil.
+	TableStats bool
+}
+
+// Report is information about the data processed by Aggregate.
+type Report struct {
+	// Lines is the number of lines, only counted by ModeStrict and
+	// ModeLenient.
+	Lines int64
+	// Rejects is the number of lines skipped by ModeLenient per Reason.
+	Rejects RejectCounts
+	// Stations is the number of different stations.
+	Stations int
+	// WorkerTables are the statistics of the hash tables of the chunks, or
+	// the blocks of a stream, in the order of the data. MergedTable are the
+	// statistics of the hash table of the result, counting the lookups of the
+	// last merge. Only collected if Options.TableStats is set.
+	WorkerTables []TableStats
+	MergedTable  TableStats
+}
+
+// ErrTooManyStations is returned by ModeStrict, if the data contains more
+// than MaxStations different stations. The other modes allow any number of
+// stations.
+var ErrTooManyStations = errors.New("too many different stations")
+
+func (o Options) numWorkers() int {
+	if o.NumWorkers > 0 {
+		return o.NumWorkers
+	}
+	return 10 * runtime.NumCPU()
+}
+
+// tableStats returns the statistics of the hash table `table` of a chunk, if
+// they are collected. `counts` is the number of lookups of each station.
+func (o Options) tableStats(table *StationTable, counts []uint) []TableStats {
+	if !o.TableStats {
+		return nil
+	}
+	return []TableStats{table.Stats(counts)}
+}
+
+// newTable returns an empty hash table of the station names.
+func (o Options) newTable() *StationTable {
+	maxProbe := o.MaxProbe
+	if maxProbe == 0 && o.FloodResistant {
+		maxProbe = DefaultMaxProbe
+	}
+	return NewStationTable(DefaultTableBits, o.LoadFactor, maxProbe)
+}
+
+func (o Options) hasher() Hasher {
+	if o.Hasher != nil {
+		return o.Hasher
+	}
+	if o.FloodResistant {
+		return seededHasher
+	}
+	return fnvHasher{}
+}
+
+func (o Options) numSummers() int {
+	if o.NumSummers > 0 {
+		return o.NumSummers
+	}
+	return 2
+}
+
+type chunk struct {
+	StartIdx int64
+	EndIdx   int64
+}
+
+// dataChunk is a chunk of the data, which starts at byte offset Offset.
+type dataChunk struct {
+	Content []byte
+	Offset  int64
+}
+
+type resultType struct {
+	Temps StationTemperatures
+	Table *StationTable
+	// Lines is the number of lines, only counted if the lines are checked.
+	Lines int64
+	// Err is the first invalid line of ModeStrict.
+	Err *ValidationError
+	// Rejects is the number of lines skipped by ModeLenient per Reason.
+	Rejects RejectCounts
+	// RejectedLines are the lines skipped by ModeLenient, without newline.
+	RejectedLines [][]byte
+	// TableStats are the statistics of the hash tables of the chunks, and
+	// Lookups the number of lookups of each station when merging, only if
+	// Options.TableStats is set.
+	TableStats []TableStats
+	Lookups    []uint
+}
+
+// Aggregate calculates the minimum, mean and maximum temperature of each
+// weather station in the file `fileName`.
+// A regular file is mapped into memory and processed in parallel, anything
+// else, like a named pipe, is read as a stream using AggregateReader.
+// Gzip and bzip2 compressed files are decompressed, the members of a gzip file
+// consisting of more than one member are decompressed in parallel.
+func Aggregate(fileName string, opts Options) (results Results, err error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file '%s': %w", fileName, err)
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error getting data of file '%s': %w", fileName, err)
+	}
+
+	if !stat.Mode().IsRegular() {
+		return AggregateReader(file, opts)
+	}
+
+	size := stat.Size()
+	// Mmap does not like empty files.
+	if size == 0 {
+		return Results{}, nil
+	}
+
+	compression, err := fileCompression(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file '%s': %w", fileName, err)
+	}
+	switch compression {
+	case compressionGzip:
+		return aggregateGzipFile(file, size, opts)
+	case compressionBzip2:
+		return AggregateReader(file, opts)
+	}
+
+	content, err := syscall.Mmap(int(file.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, fmt.Errorf("error mapping file '%s': %w", fileName, err)
+	}
+	defer func() {
+		unmapErr := syscall.Munmap(content)
+		if unmapErr != nil && err == nil {
+			err = fmt.Errorf("error unmapping file '%s': %w", fileName, unmapErr)
+		}
+	}()
+
+	return AggregateBytes(content, opts)
+}
+
+// AggregateBytes calculates the minimum, mean and maximum temperature of each
+// weather station in `content`, which must be in the format of the
+// measurements file.
+func AggregateBytes(content []byte, opts Options) (Results, error) {
+	if len(content) == 0 {
+		return Results{}, nil
+	}
+
+	chunks := splitContent(content, opts.numWorkers())
+
+	channels := make([]chan resultType, len(chunks))
+	for idx, chunk := range chunks {
+		// non-blocking channels
+		channels[idx] = make(chan resultType, 1)
+		go processDataChunk(chunk, opts, channels[idx])
+	}
+
+	numSumChans := min(opts.numSummers(), len(channels))
+	sumChannels := make([]chan resultType, numSumChans)
+	for i := 0; i < numSumChans; i++ {
+		sumChannels[i] = make(chan resultType, 1)
+		from := i * len(channels) / numSumChans
+		to := (i + 1) * len(channels) / numSumChans
+		go sumResults(channels[from:to], opts, sumChannels[i])
+	}
+
+	total := make(chan resultType, 1)
+	sumResults(sumChannels, opts, total)
+	result := <-total
+	if result.Err != nil {
+		return nil, result.Err
+	}
+
+	if opts.RejectWriter != nil {
+		err := writeLines(opts.RejectWriter, result.RejectedLines)
+		if err != nil {
+			return nil, fmt.Errorf("error writing rejected lines: %w", err)
+		}
+	}
+
+	return finishResults(result, opts)
+}
+
+// processDataChunk processes `chunk` using the parser of the Mode of `opts`.
+func processDataChunk(chunk dataChunk, opts Options, channel chan resultType) {
+	switch opts.Mode {
+	case ModeStrict, ModeLenient:
+		processChunkChecked(chunk.Content, chunk.Offset, opts, channel)
+	default:
+		processChunk(chunk.Content, opts, channel)
+	}
+}
+
+// finishResults fills the report of `opts` and returns the sorted results.
+// In ModeStrict, more than MaxStations stations are an error.
+func finishResults(result resultType, opts Options) (Results, error) {
+	results := newResults(result)
+	if opts.Report != nil {
+		opts.Report.Lines = result.Lines
+		opts.Report.Rejects = result.Rejects
+		opts.Report.Stations = len(results)
+		if opts.TableStats {
+			opts.Report.WorkerTables = result.TableStats
+			opts.Report.MergedTable = result.Table.Stats(result.Lookups)
+		}
+	}
+	if opts.Mode == ModeStrict && len(results) > MaxStations {
+		return nil, fmt.Errorf("%w: %d, the rules allow at most %d", ErrTooManyStations, len(results), MaxStations)
+	}
+	return results, nil
+}
+
+// writeLines writes all `lines` followed by a newline to `w`.
+func writeLines(w io.Writer, lines [][]byte) error {
+	bw := bufio.NewWriter(w)
+	for _, line := range lines {
+		bw.Write(line)
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// splitContent returns the chunks of `content` to process in parallel, each
+// chunk ends with a newline.
+func splitContent(content []byte, numCPUs int) []dataChunk {
+	// The parser needs a newline at the end of each line, so copy the last
+	// line if the newline is missing.
+	var lastLine []byte
+	if content[len(content)-1] != '\n' {
+		lastNewline := bytes.LastIndexByte(content, '\n')
+		lastLine = make([]byte, 0, len(content)-lastNewline)
+		lastLine = append(lastLine, content[lastNewline+1:]...)
+		lastLine = append(lastLine, '\n')
+		content = content[:lastNewline+1]
+	}
+
+	chunks := make([]dataChunk, 0, numCPUs+1)
+	if len(content) > 0 {
+		for _, chunk := range generateChunkIndices(numCPUs, content) {
+			chunks = append(chunks, dataChunk{
+				Content: content[chunk.StartIdx : chunk.EndIdx+1],
+				Offset:  chunk.StartIdx,
+			})
+		}
+	}
+	if lastLine != nil {
+		chunks = append(chunks, dataChunk{Content: lastLine, Offset: int64(len(content))})
+	}
+	return chunks
+}
+
+func generateChunkIndices(numCPUs int, content []byte) []chunk {
+	size := int64(len(content))
+	chunkSize := size / int64(numCPUs)
+
+	chunkList := make([]chunk, 0, numCPUs)
+	chunkList = append(chunkList, chunk{
+		StartIdx: 0,
+		EndIdx:   size - 1,
+	})
+
+	for cpuIdx := 1; cpuIdx < numCPUs; cpuIdx++ {
+		// Chunks smaller than a line would start in the previous chunk.
+		readOff := max(int64(cpuIdx)*chunkSize, chunkList[cpuIdx-1].StartIdx)
+		newlineIdx := bytes.IndexByte(content[readOff:min(readOff+maxLineLength, size)], '\n')
+		if newlineIdx < 0 || readOff+int64(newlineIdx) >= size-1 {
+			break
+		}
+		chunkList = append(chunkList, chunk{
+			StartIdx: readOff + int64(newlineIdx) + 1,
+			EndIdx:   size - 1,
+		})
+		chunkList[cpuIdx-1].EndIdx = readOff + int64(newlineIdx)
+	}
+	return chunkList
+}
+
+func sumResults(channels []chan resultType, opts Options, result chan resultType) {
+	sum := newResultSum(opts)
+	for _, channel := range channels {
+		sum.add(<-channel)
+	}
+	result <- sum.result()
+}
+
+// resultSum is the sum of the results of chunks, which are added in the order
+// of the data.
+type resultSum struct {
+	stationSumData StationTemperatures
+	table          *StationTable
+	lines          int64
+	err            *ValidationError
+	rejects        RejectCounts
+	rejectedLines  [][]byte
+	tableStats     []TableStats
+	// lookups is nil, if the statistics of the tables aren't collected.
+	lookups []uint
+}
+
+func newResultSum(opts Options) *resultSum {
+	sum := &resultSum{
+		stationSumData: NewStationTemperatures(MaxStations),
+		table:          opts.newTable(),
+	}
+	if opts.TableStats {
+		sum.lookups = make([]uint, 0, MaxStations)
+	}
+	return sum
+}
+
+func (s *resultSum) add(result resultType) {
+	// The results are in the order of the data, so the line number of an
+	// error is the number of lines before this result plus the line in this
+	// result. All results after an error are ignored.
+	if s.err != nil {
+		return
+	}
+	if result.Err != nil {
+		s.err = result.Err
+		s.err.Line += s.lines
+		return
+	}
+	s.lines += result.Lines
+	s.rejects.add(&result.Rejects)
+	s.rejectedLines = append(s.rejectedLines, result.RejectedLines...)
+	s.tableStats = append(s.tableStats, result.TableStats...)
+
+	stationData := result.Temps
+	stationSumData := s.stationSumData
+	table := result.Table
+	// All tables use the same hash function, so the hashes are reused.
+	for idx := range table.Len() {
+		stIdx, isNew := s.table.IndexString(table.Name(idx), table.Hash(idx))
+		if s.lookups != nil {
+			if isNew {
+				s.lookups = append(s.lookups, 0)
+			}
+			s.lookups[stIdx]++
+		}
+		if isNew {
+			stationSumData.Reserve(stIdx)
+			stationSumData.TempSum[stIdx] = stationData.TempSum[idx]
+			stationSumData.Count[stIdx] = stationData.Count[idx]
+			stationSumData.Min[stIdx] = stationData.Min[idx]
+			stationSumData.Max[stIdx] = stationData.Max[idx]
+			continue
+		}
+		stationSumData.TempSum[stIdx] += stationData.TempSum[idx]
+		stationSumData.Count[stIdx] += stationData.Count[idx]
+		stationSumData.Min[stIdx] = min(stationData.Min[idx], stationSumData.Min[stIdx])
+		stationSumData.Max[stIdx] = max(stationData.Max[idx], stationSumData.Max[stIdx])
+	}
+	// Reserve may have grown the arrays.
+	s.stationSumData = stationSumData
+}
+
+func (s *resultSum) result() resultType {
+	return resultType{
+		Temps:         s.stationSumData,
+		Table:         s.table,
+		Lines:         s.lines,
+		Err:           s.err,
+		Rejects:       s.rejects,
+		RejectedLines: s.rejectedLines,
+		TableStats:    s.tableStats,
+		Lookups:       s.lookups,
+	}
+}
+
+func processChunk(content []byte, opts Options, channel chan resultType) {
+	table := opts.newTable()
+	hasher := opts.hasher()
+	var stationData StationTemperatures
+	// FNV-1a without a maximum probe length is the default and has its own
+	// loop, calculating the hash while searching for the semicolon and
+	// without the Go map of the table.
+	if _, isFNV := hasher.(fnvHasher); isFNV && !table.limitsProbes() {
+		stationData = processChunkFNV(content, table)
+	} else {
+		stationData = processChunkHasher(content, table, hasher)
+	}
+	channel <- resultType{Temps: stationData, Table: table, TableStats: opts.tableStats(table, stationData.Count)}
+}
+
+// processChunkFNV is processChunk using FNV-1a and a table without a maximum
+// probe length.
+func processChunkFNV(content []byte, table *StationTable) StationTemperatures {
+	stationData := NewStationTemperatures(MaxStations)
+	// Only `add` changes these.
+	slots, names, tableMask := table.slots, table.names, table.mask
+
+	// We suppose the file is valid, without a single error.
+	// Not a single error check is made.
+	for len(content) > 0 {
+
+		// Station name is not empty.
+		semiColonIdx := 1
+		currByte := content[1]
+		var nameHash uint32 = fnvOffsetBasis
+		for currByte != ';' {
+			nameHash ^= uint32(currByte)
+			nameHash *= fnvPrime
+			semiColonIdx++
+			currByte = content[semiColonIdx]
+		}
+		name := content[:semiColonIdx]
+		var temperature int = 0
+		negate := 1
+		if content[semiColonIdx+1] == '-' {
+			negate = -1
+			content = content[semiColonIdx+2:]
+		} else {
+			content = content[semiColonIdx+1:]
+		}
+
+		// Either `N.N\n` or `NN.N\n`
+		if content[1] == '.' {
+			temperature = negate * (int(content[0])*10 + int(content[2]) - 528)
+			content = content[4:]
+		} else {
+			temperature = negate * (int(content[0])*100 + int(content[1])*10 + int(content[3]) - 5328)
+			content = content[5:]
+		}
+
+		// The lookup of StationTable.Index, without counting the probes.
+		// There is always an empty slot, see StationTable.resize.
+		i := nameHash & tableMask
+		stIdx := -1
+		for {
+			slot := slots[i]
+			if slot.idx == 0 {
+				break
+			}
+			if slot.hash == nameHash && names[slot.idx-1] == string(name) {
+				stIdx = int(slot.idx - 1)
+				break
+			}
+			i = (i + 1) & tableMask
+		}
+		if stIdx < 0 {
+			stIdx = table.add(i, string(name), nameHash)
+			slots, names, tableMask = table.slots, table.names, table.mask
+			stationData.Reserve(stIdx)
+			stationData.TempSum[stIdx] = temperature
+			stationData.Count[stIdx] = 1
+			stationData.Min[stIdx] = temperature
+			stationData.Max[stIdx] = temperature
+			continue
+		}
+		stationData.TempSum[stIdx] += temperature
+		stationData.Count[stIdx]++
+		stationData.Min[stIdx] = min(stationData.Min[stIdx], temperature)
+		stationData.Max[stIdx] = max(stationData.Max[stIdx], temperature)
+	}
+	return stationData
+}
+
+// processChunkHasher is processChunk using any hash function and table.
+func processChunkHasher(content []byte, table *StationTable, hasher Hasher) StationTemperatures {
+	stationData := NewStationTemperatures(MaxStations)
+
+	// We suppose the file is valid, without a single error.
+	// Not a single error check is made.
+	for len(content) > 0 {
+
+		semiColonIdx := bytes.IndexByte(content, ';')
+		name := content[:semiColonIdx]
+		nameHash := hasher.Hash(name)
+		var temperature int = 0
+		negate := 1
+		if content[semiColonIdx+1] == '-' {
+			negate = -1
+			content = content[semiColonIdx+2:]
+		} else {
+			content = content[semiColonIdx+1:]
+		}
+
+		// Either `N.N\n` or `NN.N\n`
+		if content[1] == '.' {
+			temperature = negate * (int(content[0])*10 + int(content[2]) - 528)
+			content = content[4:]
+		} else {
+			temperature = negate * (int(content[0])*100 + int(content[1])*10 + int(content[3]) - 5328)
+			content = content[5:]
+		}
+
+		stIdx, isNew := table.Index(name, nameHash)
+		if isNew {
+			stationData.Reserve(stIdx)
+			stationData.TempSum[stIdx] = temperature
+			stationData.Count[stIdx] = 1
+			stationData.Min[stIdx] = temperature
+			stationData.Max[stIdx] = temperature
+			continue
+		}
+		stationData.TempSum[stIdx] += temperature
+		stationData.Count[stIdx]++
+		stationData.Min[stIdx] = min(stationData.Min[stIdx], temperature)
+		stationData.Max[stIdx] = max(stationData.Max[stIdx], temperature)
+	}
+	return stationData
+}
+
+func newResults(result resultType) Results {
+	results := make(Results, 0, result.Table.Len())
+	for idx := range result.Table.Len() {
+		results = append(results, Station{
+			Name:  result.Table.Name(idx),
+			Min:   result.Temps.Min[idx],
+			Max:   result.Temps.Max[idx],
+			Sum:   result.Temps.TempSum[idx],
+			Count: result.Temps.Count[idx],
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     onebrc/aggregate_test.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package onebrc_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+	"github.com/Release-Candidate/1-billion-row-challenge/reference"
+)
+
+// TestManyStations checks data with more stations than the 65,536 slots of the
+// table of go_parallel_eq.go, which must grow.
+func TestManyStations(t *testing.T) {
+	const numStations = 70_000
+	var content bytes.Buffer
+	for round := range 2 {
+		for idx := range numStations {
+			fmt.Fprintf(&content, "Station %d;%d.%d\n", idx, (idx+round)%199-99, idx%10)
+		}
+	}
+	expected, err := reference.Solve(bytes.NewReader(content.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := onebrc.Options{NumWorkers: 4, BlockSize: 1 << 16}
+	for _, tc := range []struct {
+		name      string
+		aggregate func(opts onebrc.Options) (onebrc.Results, error)
+	}{
+		{"bytes", func(opts onebrc.Options) (onebrc.Results, error) {
+			return onebrc.AggregateBytes(content.Bytes(), opts)
+		}},
+		{"stream", func(opts onebrc.Options) (onebrc.Results, error) {
+			return onebrc.AggregateReader(bytes.NewReader(content.Bytes()), opts)
+		}},
+	} {
+		for _, mode := range []onebrc.Mode{onebrc.ModeFast, onebrc.ModeLenient} {
+			t.Run(fmt.Sprintf("%s/mode %d", tc.name, mode), func(t *testing.T) {
+				opts.Mode = mode
+				results, err := tc.aggregate(opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(results) != numStations {
+					t.Errorf("got %d stations, want %d", len(results), numStations)
+				}
+				for _, difference := range onebrc.Compare(expected, results.Summaries(), 0) {
+					t.Error(difference)
+				}
+			})
+		}
+		t.Run(tc.name+"/strict", func(t *testing.T) {
+			opts.Mode = onebrc.ModeStrict
+			_, err := tc.aggregate(opts)
+			if !errors.Is(err, onebrc.ErrTooManyStations) {
+				t.Errorf("got error %v, want %v", err, onebrc.ErrTooManyStations)
+			}
+		})
+	}
+}
@@ -0,0 +1,393 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     onebrc/bench_test.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package onebrc_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/generate"
+	"github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+)
+
+// The environment variable setting the number of rows of the benchmark data,
+// like `ONEBRC_BENCH_ROWS=10_000_000`.
+const benchRowsEnv = "ONEBRC_BENCH_ROWS"
+
+const defaultBenchRows = 1_000_000
+
+var (
+	benchOnce    sync.Once
+	benchContent []byte
+	benchRows    int64
+	benchErr     error
+)
+
+// benchData returns the generated data of the benchmarks and its number of
+// rows. The data is only generated once.
+func benchData(b *testing.B) ([]byte, int64) {
+	b.Helper()
+	benchOnce.Do(func() {
+		benchRows = defaultBenchRows
+		if rowsStr := os.Getenv(benchRowsEnv); rowsStr != "" {
+			// Underscores are allowed, like `1_000_000`.
+			benchRows, benchErr = strconv.ParseInt(strings.ReplaceAll(rowsStr, "_", ""), 10, 64)
+			if benchErr != nil || benchRows <= 0 {
+				benchErr = fmt.Errorf("%s must be a positive integer, not '%s'", benchRowsEnv, rowsStr)
+				return
+			}
+		}
+		stations, err := generate.ReadStationsFile(filepath.Join("..", generate.DefaultStationsFile))
+		if err != nil {
+			benchErr = err
+			return
+		}
+		var buffer bytes.Buffer
+		_, benchErr = generate.Generate(&buffer, generate.Options{Rows: benchRows, Seed: 1, Stations: stations})
+		benchContent = buffer.Bytes()
+	})
+	if benchErr != nil {
+		b.Fatal(benchErr)
+	}
+	return benchContent, benchRows
+}
+
+// reportRows reports the number of rows per second, call it after the loop of
+// the benchmark.
+func reportRows(b *testing.B, rows int64) {
+	b.ReportMetric(float64(rows)*float64(b.N)/b.Elapsed().Seconds(), "rows/s")
+}
+
+// splitLines splits `content` into `n` chunks of about the same size, ending
+// with a newline.
+func splitLines(content []byte, n int) [][]byte {
+	chunks := make([][]byte, 0, n)
+	chunkSize := len(content)/n + 1
+	for len(content) > 0 {
+		end := min(chunkSize, len(content))
+		if newlineIdx := bytes.IndexByte(content[end-1:], '\n'); newlineIdx >= 0 {
+			end += newlineIdx
+		} else {
+			end = len(content)
+		}
+		chunks = append(chunks, content[:end])
+		content = content[end:]
+	}
+	return chunks
+}
+
+// The modes of the benchmarks.
+var benchModes = []struct {
+	name string
+	mode onebrc.Mode
+}{{"fast", onebrc.ModeFast}, {"strict", onebrc.ModeStrict}, {"lenient", onebrc.ModeLenient}}
+
+// BenchmarkAggregateBytes benchmarks the whole calculation, using all cores.
+func BenchmarkAggregateBytes(b *testing.B) {
+	content, rows := benchData(b)
+	for _, mode := range benchModes {
+		b.Run(mode.name, func(b *testing.B) {
+			b.SetBytes(int64(len(content)))
+			for range b.N {
+				_, err := onebrc.AggregateBytes(content, onebrc.Options{Mode: mode.mode})
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+			reportRows(b, rows)
+		})
+	}
+}
+
+// BenchmarkAggregateReader benchmarks the whole calculation reading the data
+// from a stream, using all cores.
+func BenchmarkAggregateReader(b *testing.B) {
+	content, rows := benchData(b)
+	for _, mode := range benchModes {
+		b.Run(mode.name, func(b *testing.B) {
+			b.SetBytes(int64(len(content)))
+			for range b.N {
+				_, err := onebrc.AggregateReader(bytes.NewReader(content), onebrc.Options{Mode: mode.mode})
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+			reportRows(b, rows)
+		})
+	}
+}
+
+// BenchmarkParse benchmarks the parsing of the data as a single chunk, using
+// the fast parser and the checking one.
+func BenchmarkParse(b *testing.B) {
+	content, rows := benchData(b)
+	b.Run("fast", func(b *testing.B) {
+		b.SetBytes(int64(len(content)))
+		for range b.N {
+			onebrc.ProcessChunk(content)
+		}
+		reportRows(b, rows)
+	})
+	for _, mode := range benchModes[1:] {
+		b.Run(mode.name, func(b *testing.B) {
+			b.SetBytes(int64(len(content)))
+			for range b.N {
+				result := onebrc.ProcessChunkChecked(content, mode.mode)
+				if result.Err != nil {
+					b.Fatal(result.Err)
+				}
+			}
+			reportRows(b, rows)
+		})
+	}
+}
+
+// Sink of the hash benchmarks, so the compiler can't remove the lookups.
+var hashSink int
+
+// fnvBytes is the 16 bit FNV-1a hash of `name` without its first byte, like
+// the parsers calculate it.
+func fnvBytes(name []byte) uint32 {
+	var hash uint32 = 2166136261
+	for _, currByte := range name[1:] {
+		hash ^= uint32(currByte)
+		hash *= 16777619
+	}
+	return hash & onebrc.HashMask
+}
+
+// BenchmarkHash benchmarks looking up the station names of all rows: the FNV
+// hash alone, the hash table using FNV comparing the names using
+// `bytes.Equal` or converting them to strings, and a Go map.
+func BenchmarkHash(b *testing.B) {
+	content, rows := benchData(b)
+	var names [][]byte
+	nameBytes := 0
+	for rest := content; len(rest) > 0; {
+		var name []byte
+		name, rest, _ = bytes.Cut(rest, []byte(";"))
+		_, rest, _ = bytes.Cut(rest, []byte("\n"))
+		names = append(names, name)
+		nameBytes += len(name)
+	}
+
+	table := make([]string, onebrc.HashMask+1)
+	stationMap := make(map[string]int, onebrc.MaxStations)
+	for _, name := range names {
+		if _, ok := stationMap[string(name)]; ok {
+			continue
+		}
+		stationMap[string(name)] = len(stationMap)
+		for i := fnvBytes(name); ; i = (i + 1) & onebrc.HashMask {
+			if table[i] == "" {
+				table[i] = string(name)
+				break
+			}
+		}
+	}
+
+	b.Run("fnv", func(b *testing.B) {
+		b.SetBytes(int64(nameBytes))
+		for range b.N {
+			for _, name := range names {
+				hashSink += int(fnvBytes(name))
+			}
+		}
+		reportRows(b, rows)
+	})
+	b.Run("fnv bytes.Equal", func(b *testing.B) {
+		b.SetBytes(int64(nameBytes))
+		for range b.N {
+			for _, name := range names {
+				i := fnvBytes(name)
+				for !bytes.Equal(name, []byte(table[i])) {
+					i = (i + 1) & onebrc.HashMask
+				}
+				hashSink += int(i)
+			}
+		}
+		reportRows(b, rows)
+	})
+	b.Run("fnv string", func(b *testing.B) {
+		b.SetBytes(int64(nameBytes))
+		for range b.N {
+			for _, name := range names {
+				i := fnvBytes(name)
+				for table[i] != string(name) {
+					i = (i + 1) & onebrc.HashMask
+				}
+				hashSink += int(i)
+			}
+		}
+		reportRows(b, rows)
+	})
+	b.Run("map", func(b *testing.B) {
+		b.SetBytes(int64(nameBytes))
+		for range b.N {
+			for _, name := range names {
+				hashSink += stationMap[string(name)]
+			}
+		}
+		reportRows(b, rows)
+	})
+}
+
+// BenchmarkHashers benchmarks the hash functions selectable by `--hash`: the
+// hashing of the station names of all rows and the whole calculation. The
+// collisions are the number of different stations with the same slot in a
+// table of HashMask+1 slots as another station.
+func BenchmarkHashers(b *testing.B) {
+	content, rows := benchData(b)
+	var names [][]byte
+	nameBytes := 0
+	for rest := content; len(rest) > 0; {
+		var name []byte
+		name, rest, _ = bytes.Cut(rest, []byte(";"))
+		_, rest, _ = bytes.Cut(rest, []byte("\n"))
+		names = append(names, name)
+		nameBytes += len(name)
+	}
+
+	for _, hasherName := range onebrc.HasherNames() {
+		hasher, err := onebrc.ParseHasher(hasherName)
+		if err != nil {
+			b.Fatal(err)
+		}
+		stations := make(map[string]bool, onebrc.MaxStations)
+		slots := make(map[uint32]bool, onebrc.MaxStations)
+		for _, name := range names {
+			if !stations[string(name)] {
+				stations[string(name)] = true
+				slots[hasher.Hash(name)&onebrc.HashMask] = true
+			}
+		}
+		collisions := float64(len(stations) - len(slots))
+
+		b.Run(hasherName+"/hash", func(b *testing.B) {
+			b.SetBytes(int64(nameBytes))
+			for range b.N {
+				for _, name := range names {
+					hashSink += int(hasher.Hash(name))
+				}
+			}
+			reportRows(b, rows)
+			b.ReportMetric(collisions, "collisions")
+		})
+		b.Run(hasherName+"/aggregate", func(b *testing.B) {
+			b.SetBytes(int64(len(content)))
+			for range b.N {
+				_, err := onebrc.AggregateBytes(content, onebrc.Options{Hasher: hasher})
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+			reportRows(b, rows)
+		})
+	}
+}
+
+// BenchmarkFlooding benchmarks the whole calculation on data of 10,000
+// stations with the same 16 bit FNV-1a hash, using the default settings and
+// resisting hash flooding.
+func BenchmarkFlooding(b *testing.B) {
+	stations, err := generate.ReadStationsFile(filepath.Join("..", generate.DefaultStationsFile))
+	if err != nil {
+		b.Fatal(err)
+	}
+	const rows = 100_000
+	var buffer bytes.Buffer
+	_, err = generate.Generate(&buffer, generate.Options{
+		Rows: rows, Seed: 1, Stations: stations, Profile: generate.ProfileFNVCollide,
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	content := buffer.Bytes()
+
+	for _, tc := range []struct {
+		name string
+		opts onebrc.Options
+	}{
+		{"default", onebrc.Options{}},
+		{"fnv max-probe", onebrc.Options{MaxProbe: onebrc.DefaultMaxProbe}},
+		{"flood-resistant", onebrc.Options{FloodResistant: true}},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			b.SetBytes(int64(len(content)))
+			for range b.N {
+				_, err := onebrc.AggregateBytes(content, tc.opts)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+			reportRows(b, rows)
+		})
+	}
+}
+
+// BenchmarkMerge benchmarks summing up the results of the chunks of the data.
+func BenchmarkMerge(b *testing.B) {
+	content, rows := benchData(b)
+	for _, numChunks := range []int{1, 8, 64} {
+		chunks := splitLines(content, numChunks)
+		results := make([]onebrc.ChunkResult, len(chunks))
+		for idx, chunk := range chunks {
+			results[idx] = onebrc.ProcessChunk(chunk)
+		}
+		b.Run(fmt.Sprintf("chunks=%d", len(chunks)), func(b *testing.B) {
+			b.SetBytes(int64(len(content)))
+			for range b.N {
+				onebrc.SumResults(results)
+			}
+			reportRows(b, rows)
+		})
+	}
+}
+
+// BenchmarkOutput benchmarks sorting the results and writing them in all
+// formats.
+func BenchmarkOutput(b *testing.B) {
+	content, _ := benchData(b)
+	result := onebrc.SumResults([]onebrc.ChunkResult{onebrc.ProcessChunk(content)})
+	results := onebrc.NewResults(result)
+
+	b.Run("sort", func(b *testing.B) {
+		for range b.N {
+			onebrc.NewResults(result)
+		}
+		b.ReportMetric(float64(len(results))*float64(b.N)/b.Elapsed().Seconds(), "stations/s")
+	})
+	for _, format := range []onebrc.Format{
+		onebrc.Format1BRC, onebrc.FormatJSON, onebrc.FormatNDJSON, onebrc.FormatCSV, onebrc.FormatTSV,
+	} {
+		var buffer bytes.Buffer
+		err := results.Write(&buffer, format)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(format.String(), func(b *testing.B) {
+			b.SetBytes(int64(buffer.Len()))
+			for range b.N {
+				err := results.Write(io.Discard, format)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(results))*float64(b.N)/b.Elapsed().Seconds(), "stations/s")
+		})
+	}
+}
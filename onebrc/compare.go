@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     onebrc/compare.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package onebrc
+
+import (
+	"fmt"
+	"math"
+)
+
+// DifferenceKind is the kind of a Difference between two results.
+type DifferenceKind int
+
+const (
+	// DifferenceMissing is a station which is missing in the actual results.
+	DifferenceMissing DifferenceKind = iota
+	// DifferenceExtra is a station which is not in the expected results.
+	DifferenceExtra
+	// DifferenceField is a field of a station with a different value.
+	DifferenceField
+)
+
+// Difference is a difference between the expected and the actual results.
+type Difference struct {
+	Kind    DifferenceKind
+	Station string
+	// Field is the name of the differing field, like `mean`, only set for
+	// DifferenceField.
+	Field string
+	// Expected and Actual are the values of the field in degrees, or the
+	// count, only set for DifferenceField.
+	Expected string
+	Actual   string
+}
+
+func (d Difference) String() string {
+	switch d.Kind {
+	case DifferenceMissing:
+		return fmt.Sprintf("missing station '%s'", d.Station)
+	case DifferenceExtra:
+		return fmt.Sprintf("extra station '%s'", d.Station)
+	default:
+		return fmt.Sprintf("station '%s': %s is %s, expected %s", d.Station, d.Field, d.Actual, d.Expected)
+	}
+}
+
+// Compare returns the differences between the `expected` and the `actual`
+// results. The min and max temperatures must be the same, the means may differ
+// by up to `meanTolerance` degrees. The count and sum are only compared, if
+// both results contain them.
+func Compare(expected Summaries, actual Summaries, meanTolerance float64) []Difference {
+	actualMap := make(map[string]Summary, len(actual))
+	for _, summary := range actual {
+		actualMap[summary.Name] = summary
+	}
+	expectedMap := make(map[string]bool, len(expected))
+
+	var differences []Difference
+	for _, exp := range expected {
+		expectedMap[exp.Name] = true
+		act, ok := actualMap[exp.Name]
+		if !ok {
+			differences = append(differences, Difference{Kind: DifferenceMissing, Station: exp.Name})
+			continue
+		}
+		differences = append(differences, compareSummary(exp, act, meanTolerance)...)
+	}
+
+	for _, act := range actual {
+		if !expectedMap[act.Name] {
+			differences = append(differences, Difference{Kind: DifferenceExtra, Station: act.Name})
+		}
+	}
+	return differences
+}
+
+func compareSummary(expected Summary, actual Summary, meanTolerance float64) []Difference {
+	var differences []Difference
+	expFields := expected.fields()
+	actFields := actual.fields()
+	addDifference := func(idx int) {
+		differences = append(differences, Difference{
+			Kind:     DifferenceField,
+			Station:  expected.Name,
+			Field:    fieldNames[idx],
+			Expected: expFields[idx],
+			Actual:   actFields[idx],
+		})
+	}
+
+	if expected.Min != actual.Min {
+		addDifference(1)
+	}
+	// Compare in tenths of a degree, the epsilon is for tolerances like `0.3`,
+	// which aren't exact as float.
+	if math.Abs(float64(expected.Mean-actual.Mean)) > meanTolerance*10+1e-9 {
+		addDifference(2)
+	}
+	if expected.Max != actual.Max {
+		addDifference(3)
+	}
+	if expected.HasCount && actual.HasCount {
+		if expected.Count != actual.Count {
+			addDifference(4)
+		}
+		if expected.Sum != actual.Sum {
+			addDifference(5)
+		}
+	}
+	return differences
+}
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     onebrc/compare_test.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package onebrc_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+)
+
+// TestCompare checks the differences found for the mean tolerance, missing
+// and extra stations and summaries with and without count.
+func TestCompare(t *testing.T) {
+	expected := onebrc.Summaries{
+		{Name: "Abha", Min: -230, Mean: 180, Max: 592, HasCount: true, Count: 2, Sum: 360},
+		{Name: "Bulawayo", Min: 89, Mean: 89, Max: 89, HasCount: true, Count: 1, Sum: 89},
+	}
+	withMean := func(mean int) onebrc.Summaries {
+		summaries := append(onebrc.Summaries(nil), expected...)
+		summaries[0].Mean = mean
+		return summaries
+	}
+	noCount := func(summaries onebrc.Summaries) onebrc.Summaries {
+		summaries = append(onebrc.Summaries(nil), summaries...)
+		for idx := range summaries {
+			summaries[idx].HasCount = false
+			summaries[idx].Count = 0
+			summaries[idx].Sum = 0
+		}
+		return summaries
+	}
+
+	for _, tc := range []struct {
+		name      string
+		actual    onebrc.Summaries
+		tolerance float64
+		want      []string
+	}{
+		{"equal", expected, 0, nil},
+		{"mean off by 0.1", withMean(181), 0, []string{"station 'Abha': mean is 18.1, expected 18.0"}},
+		{"mean within tolerance", withMean(181), 0.1, nil},
+		{"mean at tolerance", withMean(177), 0.3, nil},
+		{"mean outside tolerance", withMean(176), 0.3, []string{"station 'Abha': mean is 17.6, expected 18.0"}},
+		{"min and max not tolerated", onebrc.Summaries{
+			{Name: "Abha", Min: -229, Mean: 180, Max: 593, HasCount: true, Count: 2, Sum: 360}, expected[1],
+		}, 1, []string{"station 'Abha': min is -22.9, expected -23.0", "station 'Abha': max is 59.3, expected 59.2"}},
+		{"count and sum", onebrc.Summaries{
+			expected[0], {Name: "Bulawayo", Min: 89, Mean: 89, Max: 89, HasCount: true, Count: 2, Sum: 178},
+		}, 0, []string{"station 'Bulawayo': count is 2, expected 1", "station 'Bulawayo': sum is 17.8, expected 8.9"}},
+		{"without count", noCount(expected), 0, nil},
+		{"missing station", expected[:1], 0, []string{"missing station 'Bulawayo'"}},
+		{"extra station", append(append(onebrc.Summaries(nil), expected...), onebrc.Summary{Name: "Cabo San Lucas"}),
+			0, []string{"extra station 'Cabo San Lucas'"}},
+		{"missing and extra station", onebrc.Summaries{expected[1], {Name: "abha"}},
+			0, []string{"missing station 'Abha'", "extra station 'abha'"}},
+		{"no stations", nil, 0, []string{"missing station 'Abha'", "missing station 'Bulawayo'"}},
+	} {
+		differences := onebrc.Compare(expected, tc.actual, tc.tolerance)
+		got := make([]string, 0, len(differences))
+		for _, difference := range differences {
+			got = append(got, difference.String())
+		}
+		if fmt.Sprintf("%q", got) != fmt.Sprintf("%q", tc.want) {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+
+	// The kinds of the differences.
+	differences := onebrc.Compare(expected, onebrc.Summaries{{Name: "Abha", Mean: 180}, {Name: "x"}}, 0)
+	kinds := []onebrc.DifferenceKind{}
+	for _, difference := range differences {
+		kinds = append(kinds, difference.Kind)
+	}
+	want := []onebrc.DifferenceKind{
+		onebrc.DifferenceField, onebrc.DifferenceField, onebrc.DifferenceMissing, onebrc.DifferenceExtra,
+	}
+	if fmt.Sprint(kinds) != fmt.Sprint(want) {
+		t.Errorf("got kinds %v, want %v", kinds, want)
+	}
+}
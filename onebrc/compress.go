@@ -0,0 +1,336 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     onebrc/compress.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package onebrc
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+type compression int
+
+const (
+	compressionNone compression = iota
+	compressionGzip
+	compressionBzip2
+)
+
+// The length of the header needed by detectCompression: the bzip2 magic `BZh`,
+// the block size and the magic number of the first block or the end of the
+// stream.
+const compressionHeaderLen = 10
+
+// detectCompression returns the compression of the data starting with
+// `header`. A station name can start with `BZh`, so the magic number of the
+// first bzip2 block is checked too.
+func detectCompression(header []byte) compression {
+	switch {
+	case len(header) >= 3 && header[0] == 0x1f && header[1] == 0x8b && header[2] == 8:
+		return compressionGzip
+	case len(header) >= compressionHeaderLen &&
+		header[0] == 'B' && header[1] == 'Z' && header[2] == 'h' && header[3] >= '1' && header[3] <= '9' &&
+		(bytes.Equal(header[4:10], []byte{0x31, 0x41, 0x59, 0x26, 0x53, 0x59}) ||
+			bytes.Equal(header[4:10], []byte{0x17, 0x72, 0x45, 0x38, 0x50, 0x90})):
+		return compressionBzip2
+	}
+	return compressionNone
+}
+
+// decompressReader returns a reader decompressing `r`, if `r` is gzip or bzip2
+// compressed. Else the data of `r` is returned unchanged.
+func decompressReader(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReaderSize(r, 1<<16)
+	header, err := br.Peek(compressionHeaderLen)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	switch detectCompression(header) {
+	case compressionGzip:
+		return gzip.NewReader(br)
+	case compressionBzip2:
+		return bzip2.NewReader(br), nil
+	}
+	return br, nil
+}
+
+// fileCompression returns the compression of the file `file`.
+func fileCompression(file *os.File) (compression, error) {
+	header := make([]byte, compressionHeaderLen)
+	n, err := file.ReadAt(header, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return compressionNone, err
+	}
+	return detectCompression(header[:n]), nil
+}
+
+// aggregateGzipFile aggregates the gzip compressed file `file` of size `size`.
+// If the file consists of more than one gzip member, like files concatenated
+// using `cat`, the members are decompressed in parallel.
+func aggregateGzipFile(file *os.File, size int64, opts Options) (Results, error) {
+	members, err := findGzipMembers(file, size)
+	if err != nil {
+		return nil, fmt.Errorf("error searching gzip members: %w", err)
+	}
+	if len(members) < 2 {
+		return AggregateReader(io.NewSectionReader(file, 0, size), opts)
+	}
+
+	pr, pw := io.Pipe()
+	// Stops the decompression if AggregateReader returns early.
+	defer pr.Close()
+	go decompressGzipMembers(file, size, members, opts.numStreamWorkers(), pw)
+
+	return AggregateReader(pr, opts)
+}
+
+// isGzipHeader returns true, if `header` may be the start of a gzip member.
+// Checks the magic number, the compression method deflate, that the reserved
+// flags are not set and that the extra flags and operating system are valid.
+func isGzipHeader(header []byte) bool {
+	return len(header) >= 10 &&
+		header[0] == 0x1f && header[1] == 0x8b && header[2] == 8 &&
+		header[3]&0xe0 == 0 &&
+		(header[8] == 0 || header[8] == 2 || header[8] == 4) &&
+		(header[9] <= 13 || header[9] == 255)
+}
+
+// findGzipMembers returns the offsets of all possible gzip member headers of
+// `file`. As compressed data may contain a valid header by chance, these are
+// only candidates, which are checked when decompressing.
+func findGzipMembers(file io.ReaderAt, size int64) ([]int64, error) {
+	const bufSize = 1 << 20
+	members := []int64{0}
+	buffer := make([]byte, bufSize+compressionHeaderLen)
+	for offset := int64(1); offset < size; offset += bufSize {
+		n, err := file.ReadAt(buffer, offset)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		data := buffer[:n]
+		searchEnd := min(n, bufSize)
+		for idx := 0; idx < searchEnd; idx++ {
+			found := bytes.IndexByte(data[idx:searchEnd], 0x1f)
+			if found < 0 {
+				break
+			}
+			idx += found
+			if isGzipHeader(data[idx:]) {
+				members = append(members, offset+int64(idx))
+			}
+		}
+	}
+	return members, nil
+}
+
+const (
+	// gzipBlockSize is the size of the blocks of decompressed data.
+	gzipBlockSize = 1 << 20
+	// gzipPartBlocks is the number of blocks each decompressing goroutine can
+	// be ahead of the data written.
+	gzipPartBlocks = 4
+)
+
+// gzipPart is the part of a gzip file between two possible member starts.
+// The decompressed data is sent in blocks, `err` is set before `blocks` is
+// closed.
+type gzipPart struct {
+	blocks chan []byte
+	err    error
+	// cancel stops the decompression of a part, which isn't needed.
+	cancel chan struct{}
+}
+
+// decompressGzipMembers decompresses the parts of `file` between the possible
+// member starts `members` using `numWorkers` goroutines and writes the
+// decompressed data in order to `pw`. Each part is at most gzipPartBlocks
+// blocks ahead, so at most about numWorkers * gzipPartBlocks blocks are in
+// memory.
+func decompressGzipMembers(file io.ReaderAt, size int64, members []int64, numWorkers int, pw *io.PipeWriter) {
+	done := make(chan struct{})
+	defer close(done)
+
+	// Limits the number of parts decompressed at the same time.
+	sem := make(chan struct{}, numWorkers)
+	parts := make([]*gzipPart, len(members))
+	for idx := range parts {
+		parts[idx] = &gzipPart{
+			blocks: make(chan []byte, gzipPartBlocks),
+			cancel: make(chan struct{}),
+		}
+	}
+
+	go func() {
+		for idx, start := range members {
+			select {
+			case sem <- struct{}{}:
+			case <-done:
+				return
+			}
+			end := size
+			if idx+1 < len(members) {
+				end = members[idx+1]
+			}
+			go decompressGzipPart(file, start, end, parts[idx], done)
+		}
+	}()
+
+	pw.CloseWithError(writeGzipMembers(file, size, members, parts, sem, pw))
+}
+
+// decompressGzipPart decompresses the part of `file` from `start` to `end`.
+// This fails if `start` or `end` is not the start of a member. If `start` is
+// the start of a member, all data sent before the error is valid.
+func decompressGzipPart(file io.ReaderAt, start int64, end int64, part *gzipPart, done chan struct{}) {
+	defer close(part.blocks)
+	gz, err := gzip.NewReader(io.NewSectionReader(file, start, end-start))
+	if err != nil {
+		part.err = err
+		return
+	}
+	for {
+		block := make([]byte, gzipBlockSize)
+		n := 0
+		for n < len(block) && err == nil {
+			var read int
+			read, err = gz.Read(block[n:])
+			n += read
+		}
+		if n > 0 {
+			select {
+			case part.blocks <- block[:n]:
+			case <-part.cancel:
+				return
+			case <-done:
+				return
+			}
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				part.err = err
+			}
+			return
+		}
+	}
+}
+
+// writeGzipMembers writes the decompressed parts in order to `w`.
+// A part which failed to decompress starts at a member, but at least one of the
+// following candidates is not the start of a member. The data of this part
+// already written is valid, the rest is decompressed member by member, until
+// the end of a member is the start of another part.
+func writeGzipMembers(file io.ReaderAt, size int64, members []int64,
+	parts []*gzipPart, sem chan struct{}, w io.Writer,
+) error {
+	idx := 0
+	for idx < len(members) {
+		part := parts[idx]
+		var written int64
+		for block := range part.blocks {
+			_, err := w.Write(block)
+			if err != nil {
+				return err
+			}
+			written += int64(len(block))
+		}
+		<-sem
+		if part.err == nil {
+			idx++
+			continue
+		}
+
+		pos, err := decompressGzipUntil(file, size, members, members[idx], &skipWriter{w: w, skip: written})
+		if err != nil {
+			return err
+		}
+		next := sort.Search(len(members), func(i int) bool { return members[i] >= pos })
+		for skip := idx + 1; skip < next; skip++ {
+			close(parts[skip].cancel)
+			for range parts[skip].blocks {
+			}
+			<-sem
+		}
+		idx = next
+	}
+	return nil
+}
+
+// skipWriter discards the first `skip` bytes written to it.
+type skipWriter struct {
+	w    io.Writer
+	skip int64
+}
+
+func (s *skipWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	if s.skip >= int64(n) {
+		s.skip -= int64(n)
+		return n, nil
+	}
+	_, err := s.w.Write(p[s.skip:])
+	s.skip = 0
+	return n, err
+}
+
+// decompressGzipUntil decompresses `file` member by member starting at
+// `start`, until the end of a member is one of `members` or the end of the
+// file.
+// Returns the end of the last decompressed member.
+func decompressGzipUntil(file io.ReaderAt, size int64, members []int64, start int64, w io.Writer) (int64, error) {
+	cr := &countingReader{r: bufio.NewReader(io.NewSectionReader(file, start, size-start))}
+	gz, err := gzip.NewReader(cr)
+	if err != nil {
+		return 0, err
+	}
+	for {
+		gz.Multistream(false)
+		_, err = io.Copy(w, gz)
+		if err != nil {
+			return 0, err
+		}
+		pos := start + cr.n
+		next := sort.Search(len(members), func(i int) bool { return members[i] >= pos })
+		if pos >= size || (next < len(members) && members[next] == pos) {
+			return pos, nil
+		}
+		err = gz.Reset(cr)
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+// countingReader counts the bytes read. It implements io.ByteReader, so gzip
+// does not read ahead and the end of a member is known exactly.
+type countingReader struct {
+	r *bufio.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *countingReader) ReadByte() (byte, error) {
+	b, err := c.r.ReadByte()
+	if err == nil {
+		c.n++
+	}
+	return b, err
+}
@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     onebrc/compress_test.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package onebrc_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+)
+
+// A header of a gzip member, which is found by searching for members.
+const fakeGzipHeader = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff"
+
+// TestDetectCompression checks the detection of gzip and bzip2 by their magic
+// bytes.
+func TestDetectCompression(t *testing.T) {
+	for _, tc := range []struct {
+		header string
+		want   string
+	}{
+		{fakeGzipHeader, "gzip"},
+		{"\x1f\x8b\x08", "gzip"},
+		// Not deflate.
+		{"\x1f\x8b\x07\x00\x00\x00\x00\x00\x00\xff", "none"},
+		{"BZh91AY&SY", "bzip2"},
+		// The end of stream of an empty bzip2 file.
+		{"BZh9\x17\x72\x45\x38\x50\x90", "bzip2"},
+		// Station names starting with `BZh`.
+		{"BZh9;1.0\n", "none"},
+		{"BZh91AY&SX;1.0\n", "none"},
+		{"BZh", "none"},
+		{"Hamburg;12.0\n", "none"},
+		{"", "none"},
+	} {
+		got := onebrc.IsCompressed([]byte(tc.header))
+		if got != tc.want {
+			t.Errorf("%q: got %s, want %s", tc.header, got, tc.want)
+		}
+	}
+}
+
+// compressData returns `content` compressed with gzip `level` in `numMembers`
+// members.
+func compressData(t *testing.T, content []byte, level int, numMembers int) []byte {
+	t.Helper()
+	var buffer bytes.Buffer
+	partSize := len(content)/numMembers + 1
+	for len(content) > 0 {
+		part := content[:min(partSize, len(content))]
+		content = content[len(part):]
+		gz, err := gzip.NewWriterLevel(&buffer, level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = gz.Write(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = gz.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buffer.Bytes()
+}
+
+// compressTestData returns about 3MB of measurements, so a gzip member holds
+// more than one block of decompressed data, with `station` as one of the
+// stations.
+func compressTestData(station string) []byte {
+	var buffer bytes.Buffer
+	for idx := range 200_000 {
+		name := fmt.Sprintf("Station %d", idx%1_000)
+		if idx%1_000 == 999 {
+			name = station
+		}
+		fmt.Fprintf(&buffer, "%s;%d.%d\n", name, idx%199-99, idx%10)
+	}
+	return buffer.Bytes()
+}
+
+// TestCompressedFiles checks that plain, gzip and bzip2 compressed files yield
+// the same results, for single and multiple gzip members.
+func TestCompressedFiles(t *testing.T) {
+	// Not compressed, this header is found when searching for gzip members.
+	content := compressTestData("Fake" + fakeGzipHeader + "Station")
+	dir := t.TempDir()
+	writeFile := func(name string, data []byte) string {
+		fileName := filepath.Join(dir, name)
+		err := os.WriteFile(fileName, data, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fileName
+	}
+
+	expected, err := onebrc.AggregateBytes(content, onebrc.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(expected) != 1_000 {
+		t.Fatalf("got %d stations, want 1000", len(expected))
+	}
+
+	bzhContent := append([]byte("BZh91AY&SX;1.0\n"), content...)
+	bzhExpected, err := onebrc.AggregateBytes(bzhContent, onebrc.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(compressData(t, content, gzip.NoCompression, 1), []byte(fakeGzipHeader)) {
+		t.Fatal("the false gzip header is not in the compressed data")
+	}
+
+	testCases := []struct {
+		name     string
+		fileName string
+		expected onebrc.Results
+	}{
+		{"plain", writeFile("measurements.txt", content), expected},
+		{"gzip", writeFile("one.gz", compressData(t, content, gzip.DefaultCompression, 1)), expected},
+		{"gzip members", writeFile("members.gz", compressData(t, content, gzip.DefaultCompression, 7)), expected},
+		{"false header", writeFile("false.gz", compressData(t, content, gzip.NoCompression, 1)), expected},
+		{"members and false header", writeFile("both.gz", compressData(t, content, gzip.NoCompression, 5)), expected},
+		{"station named BZh", writeFile("bzh.txt", bzhContent), bzhExpected},
+	}
+	bzip2, err := exec.LookPath("bzip2")
+	if err == nil {
+		err = exec.Command(bzip2, "-k", testCases[0].fileName).Run()
+		if err != nil {
+			t.Fatal(err)
+		}
+		testCases = append(testCases, struct {
+			name     string
+			fileName string
+			expected onebrc.Results
+		}{"bzip2", testCases[0].fileName + ".bz2", expected})
+	} else {
+		t.Log("bzip2 not found, not testing bzip2")
+	}
+
+	for _, tc := range testCases {
+		for _, numWorkers := range []int{1, 3} {
+			t.Run(fmt.Sprintf("%s/%d workers", tc.name, numWorkers), func(t *testing.T) {
+				results, err := onebrc.Aggregate(tc.fileName, onebrc.Options{NumWorkers: numWorkers})
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, difference := range onebrc.Compare(tc.expected.Summaries(), results.Summaries(), 0) {
+					t.Error(difference)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     onebrc/export_test.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package onebrc
+
+// The internals used by the benchmarks of bench_test.go.
+
+// HashMask is the mask of the FNV hash and the size of the hash table minus
+// one.
+const HashMask = mask
+
+// ChunkResult is the result of a single chunk.
+type ChunkResult = resultType
+
+// ProcessChunk returns the result of `content` using the fast parser without
+// any checks.
+func ProcessChunk(content []byte) ChunkResult {
+	channel := make(chan resultType, 1)
+	processChunk(content, Options{}, channel)
+	return <-channel
+}
+
+// ProcessChunkChecked returns the result of `content` checking the lines
+// using `mode`.
+func ProcessChunkChecked(content []byte, mode Mode) ChunkResult {
+	channel := make(chan resultType, 1)
+	processChunkChecked(content, 0, Options{Mode: mode}, channel)
+	return <-channel
+}
+
+// SumResults returns the sum of `results` using sumResults.
+func SumResults(results []ChunkResult) ChunkResult {
+	channels := make([]chan resultType, len(results))
+	for idx, result := range results {
+		channels[idx] = make(chan resultType, 1)
+		channels[idx] <- result
+	}
+	sum := make(chan resultType, 1)
+	sumResults(channels, Options{}, sum)
+	return <-sum
+}
+
+// NewResults returns the sorted results of `result`.
+func NewResults(result ChunkResult) Results {
+	return newResults(result)
+}
+
+// IsCompressed returns the name of the compression detected by the header of
+// `data`, "gzip", "bzip2" or "none".
+func IsCompressed(data []byte) string {
+	switch detectCompression(data) {
+	case compressionGzip:
+		return "gzip"
+	case compressionBzip2:
+		return "bzip2"
+	}
+	return "none"
+}
@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     onebrc/format.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package onebrc
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format is an output format of Results.
+// All formats except Format1BRC use the same fields for each station:
+// `station`, `min`, `mean`, `max`, `count` and `sum`. The temperatures and the
+// sum are in degrees with one fractional digit.
+type Format int
+
+const (
+	// Format1BRC is the format of the challenge,
+	// `{Abha=-23.0/18.0/59.2, Abidjan=-16.2/26.0/67.3, ...}`.
+	Format1BRC Format = iota
+	// FormatJSON is a JSON array of objects, one per station.
+	FormatJSON
+	// FormatNDJSON is one JSON object per line and station.
+	FormatNDJSON
+	// FormatCSV is comma separated values with a header line.
+	FormatCSV
+	// FormatTSV is tab separated values with a header line.
+	FormatTSV
+)
+
+var formatNames = []string{"1brc", "json", "ndjson", "csv", "tsv"}
+
+// The names of the fields of a station.
+var fieldNames = []string{"station", "min", "mean", "max", "count", "sum"}
+
+func (f Format) String() string {
+	if f < 0 || int(f) >= len(formatNames) {
+		return fmt.Sprintf("unknown format %d", int(f))
+	}
+	return formatNames[f]
+}
+
+// FormatNames returns the names of all formats.
+func FormatNames() []string {
+	return append([]string(nil), formatNames...)
+}
+
+// ParseFormat returns the format with the name `name`, like `json`.
+func ParseFormat(name string) (Format, error) {
+	for idx, formatName := range formatNames {
+		if formatName == name {
+			return Format(idx), nil
+		}
+	}
+	return Format1BRC, fmt.Errorf("unknown format '%s', valid formats are: %s", name, strings.Join(formatNames, ", "))
+}
+
+// Write writes the results in the format `format` to `w`. Every format ends
+// with a newline.
+func (r Results) Write(w io.Writer, format Format) error {
+	if format == Format1BRC {
+		return r.Print(w)
+	}
+	return r.Summaries().Write(w, format)
+}
+
+// Write writes the summaries in the format `format` to `w`, like
+// Results.Write. If the summaries don't contain the count and sum, these
+// fields are `null` in JSON and empty in CSV and TSV.
+func (s Summaries) Write(w io.Writer, format Format) error {
+	switch format {
+	case Format1BRC:
+		return s.write1BRC(w)
+	case FormatJSON, FormatNDJSON:
+		return s.writeJSON(w, format == FormatNDJSON)
+	case FormatCSV:
+		return s.writeCSV(w, ',')
+	case FormatTSV:
+		return s.writeCSV(w, '\t')
+	}
+	return fmt.Errorf("unknown format %d", int(format))
+}
+
+func (s Summaries) write1BRC(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteByte('{')
+	for i, summary := range s {
+		if i > 0 {
+			bw.WriteString(", ")
+		}
+		fields := summary.fields()
+		bw.WriteString(fields[0] + "=" + fields[1] + "/" + fields[2] + "/" + fields[3])
+	}
+	bw.WriteString("}\n")
+	return bw.Flush()
+}
+
+func (s Summaries) writeJSON(w io.Writer, ndjson bool) error {
+	bw := bufio.NewWriter(w)
+	if !ndjson {
+		bw.WriteString("[\n")
+	}
+	for i, summary := range s {
+		if i > 0 && !ndjson {
+			bw.WriteString(",\n")
+		}
+		if !ndjson {
+			bw.WriteString("  ")
+		}
+		// A string can't fail to encode.
+		name, _ := json.Marshal(summary.Name)
+		fields := summary.fields()
+		fmt.Fprintf(bw, `{"%s":%s`, fieldNames[0], name)
+		for idx := 1; idx < len(fields); idx++ {
+			value := fields[idx]
+			if value == "" {
+				value = "null"
+			}
+			fmt.Fprintf(bw, `,"%s":%s`, fieldNames[idx], value)
+		}
+		bw.WriteByte('}')
+		if ndjson {
+			bw.WriteByte('\n')
+		}
+	}
+	if !ndjson {
+		if len(s) > 0 {
+			bw.WriteByte('\n')
+		}
+		bw.WriteString("]\n")
+	}
+	return bw.Flush()
+}
+
+func (s Summaries) writeCSV(w io.Writer, separator rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = separator
+	cw.Write(fieldNames)
+	for _, summary := range s {
+		cw.Write(summary.fields())
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     onebrc/format_test.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package onebrc_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+)
+
+// Stations with names which must be quoted in CSV or TSV.
+var formatResults = onebrc.Results{
+	{Name: "Abha", Min: -230, Max: 592, Sum: 360, Count: 2},
+	{Name: "Zürich", Min: -5, Max: 5, Sum: -1, Count: 3},
+	{Name: `a, "b"`, Min: 0, Max: 999, Sum: 1000, Count: 4},
+	{Name: "tab\tname", Min: -999, Max: -999, Sum: -999, Count: 1},
+}
+
+// TestWriteGolden checks the output of every format.
+func TestWriteGolden(t *testing.T) {
+	for _, tc := range []struct {
+		format onebrc.Format
+		want   string
+	}{
+		{onebrc.Format1BRC, "{Abha=-23.0/18.0/59.2, Zürich=-0.5/0.0/0.5, a, \"b\"=0.0/25.0/99.9, " +
+			"tab\tname=-99.9/-99.9/-99.9}\n"},
+		{onebrc.FormatJSON, `[
+  {"station":"Abha","min":-23.0,"mean":18.0,"max":59.2,"count":2,"sum":36.0},
+  {"station":"Zürich","min":-0.5,"mean":0.0,"max":0.5,"count":3,"sum":-0.1},
+  {"station":"a, \"b\"","min":0.0,"mean":25.0,"max":99.9,"count":4,"sum":100.0},
+  {"station":"tab\tname","min":-99.9,"mean":-99.9,"max":-99.9,"count":1,"sum":-99.9}
+]
+`},
+		{onebrc.FormatNDJSON, `{"station":"Abha","min":-23.0,"mean":18.0,"max":59.2,"count":2,"sum":36.0}
+{"station":"Zürich","min":-0.5,"mean":0.0,"max":0.5,"count":3,"sum":-0.1}
+{"station":"a, \"b\"","min":0.0,"mean":25.0,"max":99.9,"count":4,"sum":100.0}
+{"station":"tab\tname","min":-99.9,"mean":-99.9,"max":-99.9,"count":1,"sum":-99.9}
+`},
+		{onebrc.FormatCSV, "station,min,mean,max,count,sum\n" +
+			"Abha,-23.0,18.0,59.2,2,36.0\n" +
+			"Zürich,-0.5,0.0,0.5,3,-0.1\n" +
+			`"a, ""b""",0.0,25.0,99.9,4,100.0` + "\n" +
+			"tab\tname,-99.9,-99.9,-99.9,1,-99.9\n"},
+		{onebrc.FormatTSV, "station\tmin\tmean\tmax\tcount\tsum\n" +
+			"Abha\t-23.0\t18.0\t59.2\t2\t36.0\n" +
+			"Zürich\t-0.5\t0.0\t0.5\t3\t-0.1\n" +
+			`"a, ""b"""` + "\t0.0\t25.0\t99.9\t4\t100.0\n" +
+			"\"tab\tname\"\t-99.9\t-99.9\t-99.9\t1\t-99.9\n"},
+	} {
+		var buffer bytes.Buffer
+		err := formatResults.Write(&buffer, tc.format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if buffer.String() != tc.want {
+			t.Errorf("format %s: got\n%s\nwant\n%s", tc.format, buffer.String(), tc.want)
+		}
+	}
+}
+
+// TestWriteGoldenNoCount checks the output of summaries without count and
+// sum, and of empty results.
+func TestWriteGoldenNoCount(t *testing.T) {
+	summaries := onebrc.Summaries{{Name: "a,b", Min: -12, Mean: 3, Max: 45}}
+	for _, tc := range []struct {
+		format    onebrc.Format
+		summaries onebrc.Summaries
+		want      string
+	}{
+		{onebrc.Format1BRC, summaries, "{a,b=-1.2/0.3/4.5}\n"},
+		{onebrc.FormatJSON, summaries,
+			"[\n  {\"station\":\"a,b\",\"min\":-1.2,\"mean\":0.3,\"max\":4.5,\"count\":null,\"sum\":null}\n]\n"},
+		{onebrc.FormatNDJSON, summaries,
+			"{\"station\":\"a,b\",\"min\":-1.2,\"mean\":0.3,\"max\":4.5,\"count\":null,\"sum\":null}\n"},
+		{onebrc.FormatCSV, summaries, "station,min,mean,max,count,sum\n\"a,b\",-1.2,0.3,4.5,,\n"},
+		{onebrc.FormatTSV, summaries, "station\tmin\tmean\tmax\tcount\tsum\na,b\t-1.2\t0.3\t4.5\t\t\n"},
+
+		{onebrc.Format1BRC, onebrc.Summaries{}, "{}\n"},
+		{onebrc.FormatJSON, onebrc.Summaries{}, "[\n]\n"},
+		{onebrc.FormatNDJSON, onebrc.Summaries{}, ""},
+		{onebrc.FormatCSV, onebrc.Summaries{}, "station,min,mean,max,count,sum\n"},
+		{onebrc.FormatTSV, onebrc.Summaries{}, "station\tmin\tmean\tmax\tcount\tsum\n"},
+	} {
+		var buffer bytes.Buffer
+		err := tc.summaries.Write(&buffer, tc.format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if buffer.String() != tc.want {
+			t.Errorf("format %s, %d stations: got %q, want %q", tc.format, len(tc.summaries), buffer.String(), tc.want)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     onebrc/fuzz_test.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package onebrc_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/generate"
+	"github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+	"github.com/Release-Candidate/1-billion-row-challenge/reference"
+)
+
+// A valid line of the challenge. The regexp counts the characters of the
+// name, not its bytes, so the length in bytes and UTF-8 are checked
+// separately.
+var validLine = regexp.MustCompile(`^[^;\n]{1,100};-?[0-9]{1,2}\.[0-9]$`)
+
+func isValidLine(line []byte) bool {
+	if !validLine.Match(line) {
+		return false
+	}
+	name, _, _ := bytes.Cut(line, []byte(";"))
+	return len(name) <= onebrc.MaxNameLength && utf8.Valid(name)
+}
+
+// checkResults checks `results` against the reference solution of `content`.
+func checkResults(t *testing.T, content []byte, results onebrc.Results) {
+	t.Helper()
+	expected, err := reference.Solve(bytes.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, difference := range onebrc.Compare(expected, results.Summaries(), 0) {
+		t.Error(difference)
+	}
+	if len(expected) != len(results) {
+		t.Errorf("got %d stations, want %d", len(results), len(expected))
+	}
+}
+
+// FuzzLines checks that ModeStrict and ModeLenient don't panic on any data and
+// agree with a simple check of the lines. The valid lines are checked against
+// the reference solution, using ModeLenient and ModeFast.
+func FuzzLines(f *testing.F) {
+	f.Add([]byte("Hamburg;12.0\nBulawayo;8.9\n"))
+	f.Add([]byte("Hamburg;12.0\nBulawayo;8.9"))
+	f.Add([]byte("a;-99.9\nb;99.9\n;1.0\nc;\nd;1\ne;1.00\nf;100.0\ng;1.0;2.0\nh;-\ni;.5\nj;-.5\n"))
+	f.Add([]byte("\n\n;\n"))
+	f.Add([]byte("Zürich;-0.0\n\xff;1.0\n"))
+	f.Add([]byte(strings.Repeat("n", 101) + ";1.0\n" + strings.Repeat("n", 100) + ";1.0\n"))
+	f.Add([]byte(strings.Repeat("ü", 60) + ";1.0\n" + strings.Repeat("ü", 50) + ";1.0\n"))
+	f.Fuzz(func(t *testing.T, content []byte) {
+		lines := bytes.Split(bytes.TrimSuffix(content, []byte("\n")), []byte("\n"))
+		if len(content) == 0 {
+			lines = nil
+		}
+		var valid bytes.Buffer
+		var firstInvalid int64 = 0
+		var numInvalid int64 = 0
+		for idx, line := range lines {
+			if isValidLine(line) {
+				valid.Write(line)
+				valid.WriteByte('\n')
+				continue
+			}
+			numInvalid++
+			if firstInvalid == 0 {
+				firstInvalid = int64(idx) + 1
+			}
+		}
+
+		_, err := onebrc.AggregateBytes(content, onebrc.Options{Mode: onebrc.ModeStrict, NumWorkers: 3})
+		var validationErr *onebrc.ValidationError
+		switch {
+		case firstInvalid == 0 && err != nil:
+			t.Fatalf("strict mode: unexpected error %v", err)
+		case firstInvalid > 0 && !errors.As(err, &validationErr):
+			t.Fatalf("strict mode: got error %v, want invalid line %d", err, firstInvalid)
+		case firstInvalid > 0 && validationErr.Line != firstInvalid:
+			t.Fatalf("strict mode: got invalid line %d, want %d", validationErr.Line, firstInvalid)
+		}
+
+		var rejects bytes.Buffer
+		report := onebrc.Report{}
+		results, err := onebrc.AggregateBytes(content, onebrc.Options{
+			Mode:         onebrc.ModeLenient,
+			NumWorkers:   3,
+			RejectWriter: &rejects,
+			Report:       &report,
+		})
+		if err != nil {
+			t.Fatalf("lenient mode: unexpected error %v", err)
+		}
+		if report.Rejects.Total() != numInvalid || report.Lines != int64(len(lines)) {
+			t.Fatalf("lenient mode: got %d of %d lines invalid, want %d of %d",
+				report.Rejects.Total(), report.Lines, numInvalid, len(lines))
+		}
+		checkResults(t, valid.Bytes(), results)
+
+		results, err = onebrc.AggregateBytes(valid.Bytes(), onebrc.Options{NumWorkers: 3})
+		if err != nil {
+			t.Fatalf("fast mode: unexpected error %v", err)
+		}
+		checkResults(t, valid.Bytes(), results)
+	})
+}
+
+// FuzzTemperature checks the parsing of all temperatures by the fast and the
+// checking parser.
+func FuzzTemperature(f *testing.F) {
+	for _, tenths := range []int16{0, 1, -1, 9, -9, 10, -10, 99, -99, 100, -100, 999, -999} {
+		f.Add(tenths)
+	}
+	f.Fuzz(func(t *testing.T, tenths int16) {
+		tenths %= 1000
+		temperature := fmt.Sprintf("%d.%d", tenths/10, max(tenths, -tenths)%10)
+		if tenths < 0 && tenths > -10 {
+			temperature = "-" + temperature
+		}
+		content := []byte("a;" + temperature + "\nb;0.0\na;" + temperature + "\n")
+		for _, mode := range []onebrc.Mode{onebrc.ModeFast, onebrc.ModeStrict} {
+			results, err := onebrc.AggregateBytes(content, onebrc.Options{Mode: mode})
+			if err != nil {
+				t.Fatalf("mode %d: unexpected error %v for %s", mode, err, temperature)
+			}
+			if results[0].Min != int(tenths) || results[0].Max != int(tenths) {
+				t.Fatalf("mode %d: got %d/%d for %s", mode, results[0].Min, results[0].Max, temperature)
+			}
+		}
+	})
+}
+
+var fuzzStations = []generate.Station{
+	{Name: "Hamburg", Mean: 9.7},
+	{Name: "Zürich", Mean: 9.3},
+	{Name: "東京", Mean: 15.4},
+	{Name: "A", Mean: -10.0},
+	{Name: strings.Repeat("Long", 25), Mean: 20.0},
+}
+
+// FuzzChunks checks the splitting of the data into chunks and blocks using
+// random numbers of chunks and block sizes.
+func FuzzChunks(f *testing.F) {
+	f.Add(uint64(1), uint16(1), uint8(0), uint8(0), uint16(1), false)
+	f.Add(uint64(2), uint16(10), uint8(20), uint8(1), uint16(3), true)
+	f.Add(uint64(3), uint16(1000), uint8(7), uint8(2), uint16(100), false)
+	f.Fuzz(func(t *testing.T, seed uint64, rows uint16, workers uint8, summers uint8, blockSize uint16, cutNewline bool) {
+		// Every chunk and block allocates its own hash table, so not too many
+		// rows.
+		rows %= 2000
+		var buffer bytes.Buffer
+		_, err := generate.Generate(&buffer, generate.Options{
+			Rows:         int64(rows),
+			Seed:         seed,
+			Stations:     fuzzStations,
+			Distribution: generate.DistributionGaussian,
+			StdDev:       50,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		content := buffer.Bytes()
+		if cutNewline {
+			content = bytes.TrimSuffix(content, []byte("\n"))
+		}
+
+		for _, mode := range []onebrc.Mode{onebrc.ModeFast, onebrc.ModeStrict} {
+			opts := onebrc.Options{
+				NumWorkers: int(workers),
+				NumSummers: int(summers),
+				BlockSize:  int(blockSize),
+				Mode:       mode,
+			}
+			results, err := onebrc.AggregateBytes(content, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkResults(t, content, results)
+
+			results, err = onebrc.AggregateReader(bytes.NewReader(content), opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkResults(t, content, results)
+		}
+	})
+}
+
+// An entry of Format1BRC ends at the first `=min/mean/max, ` after the first
+// byte of the name, so a name containing this followed by anything - the `=`
+// after the name at least - is read as two stations.
+var ambiguous1BRC = regexp.MustCompile(`.=-?[0-9]+\.[0-9]/-?[0-9]+\.[0-9]/-?[0-9]+\.[0-9], .`)
+
+func isAmbiguous1BRC(name string) bool {
+	return ambiguous1BRC.MatchString(name + "=")
+}
+
+// FuzzResults checks that results written in every format are read back the
+// same.
+func FuzzResults(f *testing.F) {
+	f.Add("Hamburg", "Zürich", -999, 999, 12345, uint(7))
+	f.Add("a, b=c", "x/y=1.0/2.0/3.0", 0, 0, 0, uint(1))
+	f.Add(`"quoted"`, "tab\tname", -1, 1, -5, uint(3))
+	f.Add("a=1.0/2.0/3.0,b", "c=1.0/2.0/3.0, ", -1, 1, -5, uint(3))
+	f.Add("b=1.0/2.0/3.0}", "{a}=", 0, 1, 2, uint(3))
+	f.Fuzz(func(t *testing.T, name1 string, name2 string, minTemp int, maxTemp int, sum int, count uint) {
+		for _, name := range []string{name1, name2} {
+			if len(name) == 0 || len(name) > onebrc.MaxNameLength || !utf8.ValidString(name) ||
+				strings.ContainsAny(name, ";\n\r") {
+				t.Skip()
+			}
+		}
+		if name1 >= name2 || count == 0 {
+			t.Skip()
+		}
+		minTemp = min(max(minTemp, -999), 999)
+		maxTemp = min(max(maxTemp, -999), 999)
+		results := onebrc.Results{
+			{Name: name1, Min: minTemp, Max: maxTemp, Sum: sum % 1_000_000_000, Count: count % 1_000_000},
+			{Name: name2, Min: maxTemp, Max: minTemp, Sum: -sum % 1_000_000_000, Count: count%1_000_000 + 1},
+		}
+		if results[0].Count == 0 {
+			results[0].Count = 1
+		}
+
+		for _, format := range []onebrc.Format{
+			onebrc.Format1BRC, onebrc.FormatJSON, onebrc.FormatNDJSON, onebrc.FormatCSV, onebrc.FormatTSV,
+		} {
+			if format == onebrc.Format1BRC && (isAmbiguous1BRC(name1) || isAmbiguous1BRC(name2)) {
+				continue
+			}
+			var buffer bytes.Buffer
+			err := results.Write(&buffer, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			summaries, err := onebrc.ReadResults(&buffer)
+			if err != nil {
+				t.Fatalf("format %s: %v", format, err)
+			}
+			for _, difference := range onebrc.Compare(results.Summaries(), summaries, 0) {
+				t.Errorf("format %s: %s", format, difference)
+			}
+		}
+	})
+}
@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     onebrc/hash.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package onebrc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/maphash"
+	"strings"
+)
+
+// DefaultHasher is the name of the default hash function, FNV-1a.
+const DefaultHasher = "fnv"
+
+// Hasher is a hash function of station names, used to look them up in a
+// StationTable. All tables of a calculation must use the same hash function,
+// as the hashes are reused when merging them.
+type Hasher interface {
+	// Name returns the name of the hash function, like `fnv`.
+	Name() string
+	// Hash returns the hash of the station name `name`.
+	Hash(name []byte) uint32
+}
+
+// seededHasher is the hash of Options.FloodResistant, using a random seed
+// for each run of the program.
+var seededHasher = mapHasher{seed: maphash.MakeSeed()}
+
+// The hash functions, the first is the default.
+var hashers = []Hasher{
+	fnvHasher{},
+	seededHasher,
+	multiplyShiftHasher{},
+	lengthPrefixHasher{},
+}
+
+// HasherNames returns the names of all hash functions.
+func HasherNames() []string {
+	names := make([]string, 0, len(hashers))
+	for _, hasher := range hashers {
+		names = append(names, hasher.Name())
+	}
+	return names
+}
+
+// ParseHasher returns the hash function with the name `name`, like `maphash`.
+func ParseHasher(name string) (Hasher, error) {
+	for _, hasher := range hashers {
+		if hasher.Name() == name {
+			return hasher, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown hash function '%s', valid hash functions are: %s",
+		name, strings.Join(HasherNames(), ", "))
+}
+
+// fnvHasher is the 32 bit FNV-1a hash, see
+// http://www.isthe.com/chongo/tech/comp/fnv/index.html
+// The parsers calculate it inline, while searching for the semicolon. Like
+// go_parallel_fnv.go and go_parallel_eq.go, the first byte of the name is not
+// hashed, the search starts at the second byte.
+type fnvHasher struct{}
+
+func (fnvHasher) Name() string {
+	return "fnv"
+}
+
+func (fnvHasher) Hash(name []byte) uint32 {
+	var hash uint32 = fnvOffsetBasis
+	for _, currByte := range name[min(1, len(name)):] {
+		hash ^= uint32(currByte)
+		hash *= fnvPrime
+	}
+	return hash
+}
+
+// mapHasher is the hash of Go maps, using a random seed for each run of the
+// program.
+type mapHasher struct {
+	seed maphash.Seed
+}
+
+func (mapHasher) Name() string {
+	return "maphash"
+}
+
+func (h mapHasher) Hash(name []byte) uint32 {
+	return uint32(maphash.Bytes(h.seed, name))
+}
+
+// The odd 64 bit constant of Fibonacci hashing, 2^64 divided by the golden
+// ratio.
+const multiplyShiftFactor = 0x9e3779b97f4a7c15
+
+// multiplyShiftHasher reads the name 8 bytes at a time and multiplies each
+// word with multiplyShiftFactor. The upper bits of the product are the hash.
+type multiplyShiftHasher struct{}
+
+func (multiplyShiftHasher) Name() string {
+	return "multiply-shift"
+}
+
+func (multiplyShiftHasher) Hash(name []byte) uint32 {
+	hash := uint64(len(name))
+	for len(name) >= 8 {
+		hash = (hash ^ binary.LittleEndian.Uint64(name)) * multiplyShiftFactor
+		name = name[8:]
+	}
+	// The rest of less than 8 bytes.
+	var word uint64
+	for idx, currByte := range name {
+		word |= uint64(currByte) << (8 * idx)
+	}
+	hash = (hash ^ word) * multiplyShiftFactor
+	return uint32(hash >> 32)
+}
+
+// lengthPrefixHasher only uses the length and the first 4 bytes of the name.
+// It is the fastest, but names with the same length and prefix collide.
+type lengthPrefixHasher struct{}
+
+func (lengthPrefixHasher) Name() string {
+	return "length-prefix"
+}
+
+func (lengthPrefixHasher) Hash(name []byte) uint32 {
+	var prefix uint32
+	for idx, currByte := range name[:min(4, len(name))] {
+		prefix |= uint32(currByte) << (8 * idx)
+	}
+	hash := (prefix ^ uint32(len(name))<<24) * uint32(multiplyShiftFactor>>32)
+	// The table uses the lowest bits, which only depend on the lowest bits of
+	// the prefix.
+	return hash ^ hash>>16
+}
@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     onebrc/onebrc.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+// Package onebrc is the fastest Go solution of the one billion row challenge,
+// go_parallel_eq.go, as a library.
+//
+// The data file is mapped into memory using `mmap`, split into chunks which
+// are processed in parallel using a hash table with the FNV hash function and
+// the results of the chunks are summed up in parallel too.
+//
+// Uses FNV hash algorithm: http://www.isthe.com/chongo/tech/comp/fnv/index.html
+package onebrc
+
+import "math"
+
+// StationTemperatures holds the temperature data of all weather stations, the
+// index of a station is the same in all arrays.
+// All temperatures are integers, the temperature in tenths of a degree, so
+// `-12.3` is saved as `-123`.
+type StationTemperatures struct {
+	TempSum []int
+	Count   []uint
+	Min     []int
+	Max     []int
+}
+
+// NewStationTemperatures returns a StationTemperatures with room for
+// `capacity` stations.
+func NewStationTemperatures(capacity int) StationTemperatures {
+	return StationTemperatures{
+		TempSum: make([]int, capacity),
+		Count:   make([]uint, capacity),
+		Min:     make([]int, capacity),
+		Max:     make([]int, capacity),
+	}
+}
+
+// Reserve makes room for the station with index `idx`. New stations get the
+// next index, so the arrays only grow if `idx` is their length. They start with
+// room for MaxStations stations, so valid data never needs to grow them.
+func (s *StationTemperatures) Reserve(idx int) {
+	if idx >= len(s.Count) {
+		s.grow(idx + 1)
+	}
+}
+
+// grow grows the arrays to at least `capacity` stations, doubling their size.
+func (s *StationTemperatures) grow(capacity int) {
+	capacity = max(capacity, 2*len(s.Count))
+	numNew := capacity - len(s.Count)
+	s.TempSum = append(s.TempSum, make([]int, numNew)...)
+	s.Count = append(s.Count, make([]uint, numNew)...)
+	s.Min = append(s.Min, make([]int, numNew)...)
+	s.Max = append(s.Max, make([]int, numNew)...)
+}
+
+const (
+	// MaxStations is the maximum number of unique station names allowed by the
+	// rules.
+	MaxStations = 10_000
+	// MaxNameLength is the maximum length of a station name in bytes.
+	MaxNameLength = 100
+	// The longest possible line is `MaxNameLength` bytes of name, the
+	// semicolon and `-99.9\n`.
+	maxLineLength = MaxNameLength + 7
+
+	numBits        = 16
+	mask           = (1 << numBits) - 1
+	fnvPrime       = 16777619
+	fnvOffsetBasis = 2166136261
+)
+
+func roundJava(x float64) float64 {
+	rounded := math.Trunc(x)
+	if x < 0.0 && rounded-x == 0.5 {
+		// return
+	} else if math.Abs(x-rounded) >= 0.5 {
+		rounded += math.Copysign(1, x)
+	}
+
+	// oh, another hardcoded `-0.0` to `0.0` conversion.
+	if rounded == 0 {
+		return 0.0
+	}
+
+	return rounded / 10.0
+}
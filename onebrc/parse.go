@@ -0,0 +1,133 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     onebrc/parse.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package onebrc
+
+import (
+	"io"
+	"sort"
+)
+
+// The building blocks of go_single_thread_profiling.go, to write a simple
+// single threaded solution:
+//
+//	stationData := NewStationTemperatures(MaxStations)
+//	stationIdxMap := make(map[string]int, MaxStations)
+//	stationIdx := 0
+//	idx := 0
+//	for idx < len(content) {
+//		semiColonIdx, station := ParseStationName(content, idx)
+//		temperature, newLineIdx := ParseTemperature(idx, semiColonIdx, content)
+//		stationIdx = AddTemperatureData(stationIdxMap, station, &stationData, temperature, stationIdx)
+//		idx += semiColonIdx + newLineIdx + 1
+//	}
+//	keys := SortStationNames(stationIdxMap)
+//	err := PrintSolution(os.Stdout, keys, stationIdxMap, stationData)
+
+// ParseStationName returns the length of the station name starting at index
+// `idx` of `content` and the station name itself.
+// The station name must be terminated by a semicolon.
+func ParseStationName(content []byte, idx int) (int, []byte) {
+	semiColonIdx := 0
+	station := [MaxNameLength]byte{}
+	currByte := content[idx]
+	for currByte != ';' {
+		station[semiColonIdx] = currByte
+		semiColonIdx++
+		currByte = content[idx+semiColonIdx]
+	}
+	return semiColonIdx, station[:semiColonIdx]
+}
+
+// ParseTemperature parses the temperature after the station name of length
+// `semiColonIdx` starting at index `idx` of `content`.
+// Returns the temperature in tenths of a degree and the number of bytes
+// including the newline after the semicolon.
+func ParseTemperature(idx int, semiColonIdx int, content []byte) (int, int) {
+	var temperature int = 0
+	var negate int = 1
+	tmpIdx := idx + semiColonIdx + 1
+	newLineIdx := 0
+Loop:
+	for tmpIdx < len(content) {
+		currByte := content[tmpIdx]
+		tmpIdx++
+		newLineIdx++
+		switch currByte {
+		case '-':
+			negate = -1
+		case '\n':
+			break Loop
+		case '.':
+			continue
+		default:
+			intVal := currByte - '0'
+			temperature = temperature*10 + int(intVal)
+		}
+	}
+	temperature *= negate
+	return temperature, newLineIdx
+}
+
+// AddTemperatureData adds the temperature `temperature` of the station
+// `station` to `stationData`. If the station is new, it gets the index
+// `stationIdx`.
+// Returns the index of the next new station.
+func AddTemperatureData(stationIdxMap map[string]int, station []byte, stationData *StationTemperatures, temperature int, stationIdx int) int {
+	stIdx, ok := stationIdxMap[string(station)]
+	if ok {
+		stationData.TempSum[stIdx] += temperature
+		stationData.Count[stIdx]++
+		stationData.Min[stIdx] = min(stationData.Min[stIdx], temperature)
+		stationData.Max[stIdx] = max(stationData.Max[stIdx], temperature)
+	} else {
+		stationIdxMap[string(station)] = stationIdx
+		stationData.Reserve(stationIdx)
+		stationData.TempSum[stationIdx] += temperature
+		stationData.Count[stationIdx]++
+		stationData.Min[stationIdx] = temperature
+		stationData.Max[stationIdx] = temperature
+		stationIdx++
+	}
+
+	return stationIdx
+}
+
+// SortStationNames returns the station names of `stationIdxMap` sorted
+// alphabetically.
+func SortStationNames(stationIdxMap map[string]int) []string {
+	keys := make([]string, 0, len(stationIdxMap))
+	for key := range stationIdxMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// PrintSolution writes the stations `keys` in the format of the challenge,
+// `{Abha=-23.0/18.0/59.2, Abidjan=-16.2/26.0/67.3, ...}`, to `w`.
+func PrintSolution(w io.Writer, keys []string, stationIdxMap map[string]int, stationData StationTemperatures) error {
+	return ResultsFromMap(keys, stationIdxMap, stationData).Print(w)
+}
+
+// ResultsFromMap returns the stations `keys` as Results.
+func ResultsFromMap(keys []string, stationIdxMap map[string]int, stationData StationTemperatures) Results {
+	results := make(Results, 0, len(keys))
+	for _, station := range keys {
+		idx := stationIdxMap[station]
+		results = append(results, Station{
+			Name:  station,
+			Min:   stationData.Min[idx],
+			Max:   stationData.Max[idx],
+			Sum:   stationData.TempSum[idx],
+			Count: stationData.Count[idx],
+		})
+	}
+	return results
+}
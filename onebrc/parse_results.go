@@ -0,0 +1,288 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     onebrc/parse_results.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package onebrc
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParseResults parses results in the format of the challenge,
+// `{Abha=-23.0/18.0/59.2, Abidjan=-16.2/26.0/67.3, ...}`, like the
+// `correct_results.txt` of the Java reference implementation or the output of
+// the C and Haskell versions.
+// Station names may contain `,`, `=` and `/`, an entry ends at the first
+// `=min/mean/max` which is followed by `, ` or the closing `}`.
+func ParseResults(r io.Reader) (Summaries, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading results: %w", err)
+	}
+	return parseResults1BRC(data)
+}
+
+// ReadResults reads results in any of the formats written by Results.Write.
+// The format is detected by the start of the data.
+func ReadResults(r io.Reader) (Summaries, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading results: %w", err)
+	}
+
+	switch DetectFormat(data) {
+	case FormatJSON:
+		return parseResultsJSON(data)
+	case FormatNDJSON:
+		return parseResultsNDJSON(data)
+	case FormatCSV:
+		return parseResultsCSV(data, ',')
+	case FormatTSV:
+		return parseResultsCSV(data, '\t')
+	}
+	return parseResults1BRC(data)
+}
+
+// DetectFormat returns the format of the results `data`. Data which is in none
+// of the other formats is Format1BRC.
+func DetectFormat(data []byte) Format {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(data, []byte("[")):
+		return FormatJSON
+	case bytes.HasPrefix(data, []byte(`{"`+fieldNames[0]+`":`)):
+		return FormatNDJSON
+	case bytes.HasPrefix(data, []byte(fieldNames[0]+",")):
+		return FormatCSV
+	case bytes.HasPrefix(data, []byte(fieldNames[0]+"\t")):
+		return FormatTSV
+	}
+	return Format1BRC
+}
+
+func parseResults1BRC(data []byte) (Summaries, error) {
+	content := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(content, "{") || !strings.HasSuffix(content, "}") {
+		return nil, fmt.Errorf("results don't start with '{' and end with '}'")
+	}
+	content = content[1 : len(content)-1]
+	offset := strings.Index(string(data), "{") + 1
+
+	summaries := Summaries{}
+	for len(content) > 0 {
+		summary, rest, ok := parseSummary(content)
+		if !ok {
+			return nil, fmt.Errorf("invalid station result at byte offset %d", offset)
+		}
+		summaries = append(summaries, summary)
+		offset += len(content) - len(rest)
+		content = rest
+	}
+	return summaries, nil
+}
+
+// parseSummary parses the first `name=min/mean/max` of `content`.
+// Returns the summary and the rest of `content` after the separator `, `.
+func parseSummary(content string) (Summary, string, bool) {
+	// The name has at least one byte.
+	for idx := 1; idx < len(content); idx++ {
+		eqIdx := strings.IndexByte(content[idx:], '=')
+		if eqIdx < 0 {
+			break
+		}
+		idx += eqIdx
+		temps, rest, ok := parseTemperatures(content[idx+1:])
+		if !ok {
+			continue
+		}
+		if rest == "" || (len(rest) > 2 && strings.HasPrefix(rest, ", ")) {
+			return Summary{
+				Name: content[:idx],
+				Min:  temps[0],
+				Mean: temps[1],
+				Max:  temps[2],
+			}, strings.TrimPrefix(rest, ", "), true
+		}
+	}
+	return Summary{}, content, false
+}
+
+// parseTemperatures parses `min/mean/max` at the start of `content`.
+func parseTemperatures(content string) ([3]int, string, bool) {
+	var temps [3]int
+	for idx := range temps {
+		if idx > 0 {
+			if !strings.HasPrefix(content, "/") {
+				return temps, content, false
+			}
+			content = content[1:]
+		}
+		var ok bool
+		temps[idx], content, ok = parseTenths(content)
+		if !ok {
+			return temps, content, false
+		}
+	}
+	return temps, content, true
+}
+
+// parseTenths parses a temperature with exactly one fractional digit at the
+// start of `content` and returns it in tenths of a degree.
+func parseTenths(content string) (int, string, bool) {
+	negate := 1
+	if strings.HasPrefix(content, "-") {
+		negate = -1
+		content = content[1:]
+	}
+
+	temperature := 0
+	idx := 0
+	for idx < len(content) && content[idx] >= '0' && content[idx] <= '9' {
+		temperature = temperature*10 + int(content[idx]-'0')
+		idx++
+	}
+	if idx == 0 || idx+1 >= len(content) || content[idx] != '.' ||
+		content[idx+1] < '0' || content[idx+1] > '9' {
+		return 0, content, false
+	}
+	temperature = temperature*10 + int(content[idx+1]-'0')
+	return negate * temperature, content[idx+2:], true
+}
+
+// jsonSummary is a station of FormatJSON and FormatNDJSON.
+type jsonSummary struct {
+	Station *string      `json:"station"`
+	Min     *json.Number `json:"min"`
+	Mean    *json.Number `json:"mean"`
+	Max     *json.Number `json:"max"`
+	Count   *json.Number `json:"count"`
+	Sum     *json.Number `json:"sum"`
+}
+
+func (j jsonSummary) summary() (Summary, error) {
+	if j.Station == nil || j.Min == nil || j.Mean == nil || j.Max == nil {
+		return Summary{}, errors.New("missing field, needs at least station, min, mean and max")
+	}
+	values := []string{string(*j.Min), string(*j.Mean), string(*j.Max), "", ""}
+	if j.Count != nil && j.Sum != nil {
+		values[3] = string(*j.Count)
+		values[4] = string(*j.Sum)
+	}
+	return newSummary(*j.Station, values)
+}
+
+func parseResultsJSON(data []byte) (Summaries, error) {
+	var stations []jsonSummary
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	err := decoder.Decode(&stations)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing JSON results: %w", err)
+	}
+
+	summaries := make(Summaries, 0, len(stations))
+	for idx, station := range stations {
+		summary, err := station.summary()
+		if err != nil {
+			return nil, fmt.Errorf("error parsing station %d: %w", idx+1, err)
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+func parseResultsNDJSON(data []byte) (Summaries, error) {
+	summaries := Summaries{}
+	for lineNum, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var station jsonSummary
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.UseNumber()
+		err := decoder.Decode(&station)
+		if err == nil {
+			var summary Summary
+			summary, err = station.summary()
+			summaries = append(summaries, summary)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing line %d: %w", lineNum+1, err)
+		}
+	}
+	return summaries, nil
+}
+
+func parseResultsCSV(data []byte, separator rune) (Summaries, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = separator
+	reader.FieldsPerRecord = len(fieldNames)
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing results: %w", err)
+	}
+
+	summaries := make(Summaries, 0, len(records))
+	// The first record is the header.
+	for idx := 1; idx < len(records); idx++ {
+		summary, err := newSummary(records[idx][0], records[idx][1:])
+		if err != nil {
+			return nil, fmt.Errorf("error parsing line %d: %w", idx+1, err)
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// newSummary returns the summary of the station `name` with the values of the
+// other fields `values` as strings, in the order of fieldNames. If the count
+// or sum is empty, the summary has no count.
+func newSummary(name string, values []string) (Summary, error) {
+	summary := Summary{Name: name}
+	var err error
+	for idx, field := range []*int{&summary.Min, &summary.Mean, &summary.Max} {
+		*field, err = parseDegrees(values[idx])
+		if err != nil {
+			return Summary{}, fmt.Errorf("invalid %s '%s'", fieldNames[idx+1], values[idx])
+		}
+	}
+
+	if values[3] == "" || values[4] == "" {
+		return summary, nil
+	}
+	count, err := strconv.ParseUint(values[3], 10, 0)
+	if err != nil {
+		return Summary{}, fmt.Errorf("invalid count '%s'", values[3])
+	}
+	summary.Sum, err = parseDegrees(values[4])
+	if err != nil {
+		return Summary{}, fmt.Errorf("invalid sum '%s'", values[4])
+	}
+	summary.Count = uint(count)
+	summary.HasCount = true
+	return summary, nil
+}
+
+// parseDegrees parses the temperature `value` in degrees and returns it in
+// tenths of a degree. Other tools may write `18` instead of `18.0`, so any
+// number is accepted.
+func parseDegrees(value string) (int, error) {
+	degrees, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	return int(math.Round(degrees * 10)), nil
+}
@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     onebrc/parse_results_test.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package onebrc_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+)
+
+// TestParseResults checks station names containing `=`, `, ` and `/`,
+// negative zero and malformed braces.
+func TestParseResults(t *testing.T) {
+	for _, tc := range []struct {
+		results string
+		want    onebrc.Summaries
+	}{
+		{"{}", onebrc.Summaries{}},
+		{"  {}\n", onebrc.Summaries{}},
+		{"{Abha=-23.0/18.0/59.2, Abidjan=-16.2/26.0/67.3}\n", onebrc.Summaries{
+			{Name: "Abha", Min: -230, Mean: 180, Max: 592},
+			{Name: "Abidjan", Min: -162, Mean: 260, Max: 673},
+		}},
+		{"{a=b=1.0/2.0/3.0}", onebrc.Summaries{{Name: "a=b", Min: 10, Mean: 20, Max: 30}}},
+		{"{a=1.0=1.0/2.0/3.0}", onebrc.Summaries{{Name: "a=1.0", Min: 10, Mean: 20, Max: 30}}},
+		{"{a=1.0/2.0/3.0=1.0/2.0/3.0}", onebrc.Summaries{{Name: "a=1.0/2.0/3.0", Min: 10, Mean: 20, Max: 30}}},
+		{"{==1.0/2.0/3.0}", onebrc.Summaries{{Name: "=", Min: 10, Mean: 20, Max: 30}}},
+		{"{a, b=1.0/2.0/3.0, c,=4.0/5.0/6.0}", onebrc.Summaries{
+			{Name: "a, b", Min: 10, Mean: 20, Max: 30},
+			{Name: "c,", Min: 40, Mean: 50, Max: 60},
+		}},
+		{"{a=1.0/2.0/3.0,b=1.0/2.0/3.0}", onebrc.Summaries{{Name: "a=1.0/2.0/3.0,b", Min: 10, Mean: 20, Max: 30}}},
+		{"{x/y=-0.0/-0.0/0.0, {z}=-0.1/0.0/-0.0}", onebrc.Summaries{
+			{Name: "x/y", Min: 0, Mean: 0, Max: 0},
+			{Name: "{z}", Min: -1, Mean: 0, Max: 0},
+		}},
+		{"{}=-99.9/-12.3/99.9}", onebrc.Summaries{{Name: "}", Min: -999, Mean: -123, Max: 999}}},
+	} {
+		summaries, err := onebrc.ParseResults(strings.NewReader(tc.results))
+		if err != nil {
+			t.Errorf("%q: got error %v", tc.results, err)
+			continue
+		}
+		if fmt.Sprint(summaries) != fmt.Sprint(tc.want) {
+			t.Errorf("%q: got %+v, want %+v", tc.results, summaries, tc.want)
+		}
+	}
+}
+
+// TestParseResultsInvalid checks that malformed results are an error.
+func TestParseResultsInvalid(t *testing.T) {
+	for _, results := range []string{
+		"",
+		"{",
+		"}",
+		"Abha=-23.0/18.0/59.2",
+		"{Abha=-23.0/18.0/59.2",
+		"Abha=-23.0/18.0/59.2}",
+		"[Abha=-23.0/18.0/59.2]",
+		"{Abha=-23.0/18.0/59.2}}",
+		"{Abha=-23.0/18.0/59.2,}",
+		"{Abha=-23.0/18.0/59.2, }",
+		"{Abha=-23.0/18.0}",
+		"{Abha=-23/18.0/59.2}",
+		"{Abha=-23.0/18.0/59.2 }",
+		"{=1.0/2.0/3.0}",
+		"{Abha}",
+	} {
+		summaries, err := onebrc.ParseResults(strings.NewReader(results))
+		if err == nil {
+			t.Errorf("%q: got %+v, want an error", results, summaries)
+		}
+	}
+}
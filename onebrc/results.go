@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     onebrc/results.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package onebrc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Station is the aggregated temperature data of a single weather station.
+// The temperatures are in tenths of a degree, so `-12.3` is saved as `-123`.
+type Station struct {
+	Name  string
+	Min   int
+	Max   int
+	Sum   int
+	Count uint
+}
+
+// MinTemp returns the minimum temperature in degrees.
+func (s Station) MinTemp() float64 {
+	return roundJava(float64(s.Min))
+}
+
+// MeanTemp returns the mean temperature in degrees, rounded to one fractional
+// digit like the Java reference implementation does.
+func (s Station) MeanTemp() float64 {
+	return roundJava(float64(s.Sum) / float64(s.Count))
+}
+
+// MaxTemp returns the maximum temperature in degrees.
+func (s Station) MaxTemp() float64 {
+	return roundJava(float64(s.Max))
+}
+
+// Results are the aggregated data of all weather stations, sorted
+// alphabetically by station name.
+type Results []Station
+
+// String returns the results in the format of the challenge,
+// `{Abha=-23.0/18.0/59.2, Abidjan=-16.2/26.0/67.3, ...}`.
+func (r Results) String() string {
+	var sb strings.Builder
+	r.write(&sb)
+	return sb.String()
+}
+
+// Print writes the results in the format of the challenge followed by a
+// newline to `w`.
+func (r Results) Print(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	r.write(bw)
+	bw.WriteByte('\n')
+	return bw.Flush()
+}
+
+func (r Results) write(w io.Writer) {
+	fmt.Fprintf(w, "{")
+	for i, station := range r {
+		if i > 0 {
+			fmt.Fprintf(w, ", ")
+		}
+		fmt.Fprintf(w, "%s=%.1f/%.1f/%.1f", station.Name,
+			station.MinTemp(),
+			station.MeanTemp(),
+			station.MaxTemp())
+	}
+	fmt.Fprintf(w, "}")
+}
@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     onebrc/stats.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package onebrc
+
+// TableStats are statistics of a StationTable, to tune the size of the table
+// and the hash function for a dataset, see `--stats`.
+type TableStats struct {
+	// Stations is the number of stations.
+	Stations int
+	// Slots is the number of slots.
+	Slots int
+	// Overflow is the number of stations in the Go map, see MaxProbe.
+	Overflow int
+	// Colliding is the number of stations not in the slot of their hash.
+	Colliding int
+	// Collisions is the number of slots containing another station searched
+	// by all lookups, like `colls` of go_parallel_fnv.go.
+	Collisions uint64
+	// ProbeLengths is the histogram of the probe lengths, ProbeLengths[n] is
+	// the number of stations n slots after the slot of their hash. Stations
+	// in the Go map are not counted.
+	ProbeLengths []int
+	// MaxChain is the longest run of consecutive used slots.
+	MaxChain int
+}
+
+// FillRatio returns the ratio of used slots.
+func (s TableStats) FillRatio() float64 {
+	if s.Slots == 0 {
+		return 0
+	}
+	return float64(s.Stations-s.Overflow) / float64(s.Slots)
+}
+
+// MaxProbe returns the longest probe length of the stations in the table.
+func (s TableStats) MaxProbe() int {
+	return max(len(s.ProbeLengths)-1, 0)
+}
+
+// addProbeLengths adds the probe length histogram of `other` to `s`.
+func (s *TableStats) addProbeLengths(other TableStats) {
+	for len(s.ProbeLengths) < len(other.ProbeLengths) {
+		s.ProbeLengths = append(s.ProbeLengths, 0)
+	}
+	for probe, count := range other.ProbeLengths {
+		s.ProbeLengths[probe] += count
+	}
+}
+
+// SumProbeLengths returns the sum of the probe length histograms of `stats`.
+func SumProbeLengths(stats []TableStats) []int {
+	var sum TableStats
+	for _, tableStats := range stats {
+		sum.addProbeLengths(tableStats)
+	}
+	return sum.ProbeLengths
+}
+
+// Stats returns the statistics of the table. `lookups` is the number of
+// lookups of each station, to count the collisions. If it is nil, every
+// station is counted as looked up once.
+func (t *StationTable) Stats(lookups []uint) TableStats {
+	stats := TableStats{
+		Stations: t.Len(),
+		Slots:    t.Size(),
+		Overflow: t.Overflow(),
+	}
+	numLookups := func(idx int) uint64 {
+		if lookups == nil {
+			return 1
+		}
+		return uint64(lookups[idx])
+	}
+
+	for slotIdx, slot := range t.slots {
+		if slot.idx == 0 {
+			continue
+		}
+		idx := int(slot.idx - 1)
+		// The distance to the slot of the hash, wrapping around.
+		probe := int((uint32(slotIdx) - t.hashes[idx]) & t.mask)
+		for len(stats.ProbeLengths) <= probe {
+			stats.ProbeLengths = append(stats.ProbeLengths, 0)
+		}
+		stats.ProbeLengths[probe]++
+		if probe > 0 {
+			stats.Colliding++
+		}
+		stats.Collisions += uint64(probe) * numLookups(idx)
+	}
+	// The lookups of stations in the Go map search all `maxProbe` slots.
+	for _, idx := range t.overflow {
+		stats.Colliding++
+		stats.Collisions += uint64(t.maxProbe) * numLookups(idx)
+	}
+
+	// There is always an empty slot, start the runs after it, so a run
+	// wrapping around the end of the table is not split.
+	start := 0
+	for t.slots[start].idx != 0 {
+		start++
+	}
+	chain := 0
+	for i := range len(t.slots) {
+		if t.slots[(start+i)&int(t.mask)].idx == 0 {
+			chain = 0
+			continue
+		}
+		chain++
+		stats.MaxChain = max(stats.MaxChain, chain)
+	}
+	return stats
+}
@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     onebrc/stream.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package onebrc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+)
+
+const defaultBlockSize = 8 * 1024 * 1024
+
+func (o Options) numStreamWorkers() int {
+	if o.NumWorkers > 0 {
+		return o.NumWorkers
+	}
+	return runtime.NumCPU()
+}
+
+func (o Options) blockSize() int {
+	if o.BlockSize > 0 {
+		return o.BlockSize
+	}
+	return defaultBlockSize
+}
+
+// streamBlock is a block of the stream and the channel to send its result to.
+type streamBlock struct {
+	chunk   dataChunk
+	channel chan resultType
+}
+
+// AggregateReader calculates the minimum, mean and maximum temperature of each
+// weather station in the data read from `r`, like stdin or a pipe.
+// The data is split into blocks ending with a newline, which are processed in
+// parallel. Gzip and bzip2 compressed data is decompressed.
+func AggregateReader(r io.Reader, opts Options) (Results, error) {
+	r, err := decompressReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading data: %w", err)
+	}
+	numWorkers := opts.numStreamWorkers()
+
+	// The channels of the results in the order of the blocks, this also limits
+	// the number of blocks in memory.
+	results := make(chan chan resultType, numWorkers)
+	blocks := make(chan streamBlock, numWorkers)
+	done := make(chan struct{})
+	readErr := make(chan error, 1)
+
+	go func() {
+		defer close(results)
+		defer close(blocks)
+		readErr <- readBlocks(r, opts.blockSize(), func(chunk dataChunk) bool {
+			// non-blocking channel
+			channel := make(chan resultType, 1)
+			select {
+			case results <- channel:
+			case <-done:
+				return false
+			}
+			blocks <- streamBlock{chunk: chunk, channel: channel}
+			return true
+		})
+	}()
+
+	for i := 0; i < numWorkers; i++ {
+		go func() {
+			for block := range blocks {
+				processDataChunk(block.chunk, opts, block.channel)
+			}
+		}()
+	}
+
+	sum := newResultSum(opts)
+	for channel := range results {
+		sum.add(<-channel)
+		if sum.err != nil {
+			// Stop reading the stream.
+			close(done)
+			return nil, sum.err
+		}
+		// Write the rejected lines as soon as possible, the stream may be
+		// large.
+		if opts.RejectWriter != nil {
+			err := writeLines(opts.RejectWriter, sum.rejectedLines)
+			if err != nil {
+				close(done)
+				return nil, fmt.Errorf("error writing rejected lines: %w", err)
+			}
+			sum.rejectedLines = nil
+		}
+	}
+
+	err = <-readErr
+	if err != nil {
+		return nil, fmt.Errorf("error reading data: %w", err)
+	}
+
+	return finishResults(sum.result(), opts)
+}
+
+// readBlocks reads `r` in blocks of about `blockSize` bytes, each ending with
+// a newline, and calls `send` with each block. A newline is added to the last
+// line, if it is missing. A line longer than `blockSize` is read as a whole.
+// Stops if `send` returns false.
+func readBlocks(r io.Reader, blockSize int, send func(chunk dataChunk) bool) error {
+	var offset int64 = 0
+	var leftover []byte
+	for {
+		// Every block is a new buffer, as the blocks are processed in parallel
+		// and rejected lines may still point into it.
+		buffer := make([]byte, len(leftover), max(blockSize, 2*len(leftover)))
+		copy(buffer, leftover)
+		n, err := io.ReadFull(r, buffer[len(leftover):cap(buffer)])
+		buffer = buffer[:len(leftover)+n]
+
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			if len(buffer) == 0 {
+				return nil
+			}
+			if buffer[len(buffer)-1] != '\n' {
+				buffer = append(buffer, '\n')
+			}
+			send(dataChunk{Content: buffer, Offset: offset})
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		lastNewline := bytes.LastIndexByte(buffer, '\n')
+		if lastNewline < 0 {
+			leftover = buffer
+			continue
+		}
+		if !send(dataChunk{Content: buffer[:lastNewline+1], Offset: offset}) {
+			return nil
+		}
+		offset += int64(lastNewline) + 1
+		leftover = buffer[lastNewline+1:]
+	}
+}
@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     onebrc/summary.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package onebrc
+
+import (
+	"math"
+	"strconv"
+)
+
+// Summary is the result of a single weather station as it is written by
+// Results.Write, so the mean is already rounded. Unlike Station, it can be read
+// from all output formats, see ParseResults.
+// The temperatures are in tenths of a degree, so `-12.3` is saved as `-123`.
+type Summary struct {
+	Name string
+	Min  int
+	Mean int
+	Max  int
+	// HasCount is false, if the format does not contain the number of
+	// measurements and the sum of the temperatures, like Format1BRC.
+	HasCount bool
+	Count    uint
+	Sum      int
+}
+
+// Summaries are the results of all weather stations as written by
+// Results.Write.
+type Summaries []Summary
+
+// Summaries returns the results as written by Results.Write.
+func (r Results) Summaries() Summaries {
+	summaries := make(Summaries, 0, len(r))
+	for _, station := range r {
+		summaries = append(summaries, Summary{
+			Name:     station.Name,
+			Min:      station.Min,
+			Mean:     int(math.Round(station.MeanTemp() * 10)),
+			Max:      station.Max,
+			HasCount: true,
+			Count:    station.Count,
+			Sum:      station.Sum,
+		})
+	}
+	return summaries
+}
+
+// fields returns the values of the fields of the station, in the order of
+// fieldNames. The count and sum are empty, if HasCount is false.
+func (s Summary) fields() []string {
+	fields := []string{
+		s.Name,
+		formatTenths(s.Min),
+		formatTenths(s.Mean),
+		formatTenths(s.Max),
+		"",
+		"",
+	}
+	if s.HasCount {
+		fields[4] = strconv.FormatUint(uint64(s.Count), 10)
+		fields[5] = formatTenths(s.Sum)
+	}
+	return fields
+}
+
+// formatTenths returns the temperature `tenths` in tenths of a degree as
+// degrees with one fractional digit.
+func formatTenths(tenths int) string {
+	sign := ""
+	if tenths < 0 {
+		sign = "-"
+		tenths = -tenths
+	}
+	return sign + strconv.Itoa(tenths/10) + "." + strconv.Itoa(tenths%10)
+}
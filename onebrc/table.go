@@ -0,0 +1,206 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     onebrc/table.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package onebrc
+
+import "math"
+
+const (
+	// DefaultTableBits is the default size of a StationTable, 2^16 slots, the
+	// size of the hash tables of go_parallel_fnv.go and go_parallel_eq.go.
+	DefaultTableBits = numBits
+	// DefaultLoadFactor is the default maximum ratio of used slots of a
+	// StationTable.
+	DefaultLoadFactor = 0.5
+	// DefaultMaxProbe is the maximum number of slots searched by a lookup in
+	// a StationTable when resisting hash flooding, see `--flood-resistant`.
+	DefaultMaxProbe = 16
+)
+
+// StationTable is an open addressing hash table mapping station names to their
+// index in StationTemperatures, using linear probing. The stations get the
+// indices in the order they are added, starting at 0.
+// The table doubles its size, if more than its load factor of the slots are
+// used. The hash of a name is calculated by the caller, the table uses the
+// lowest bits of it.
+// If the probe length is limited, a station not found in the first `maxProbe`
+// slots is looked up in a Go map, which uses a random seed for each map. So
+// names with the same hash, which all land in one long probe sequence, can't
+// make every lookup a linear search.
+type StationTable struct {
+	slots      []tableSlot
+	mask       uint32
+	names      []string
+	hashes     []uint32
+	maxLen     int
+	loadFactor float64
+	maxProbe   int
+	overflow   map[string]int
+}
+
+// tableSlot is a slot of the hash table, `idx` is the index of the station
+// plus 1, so the zero value is an empty slot.
+type tableSlot struct {
+	hash uint32
+	idx  uint32
+}
+
+// NewStationTable returns an empty table with 2^`bits` slots, growing if more
+// than `loadFactor` of the slots are used. A load factor of 0 or less is the
+// DefaultLoadFactor. A lookup searches at most `maxProbe` slots before using
+// the Go map, 0 or less is no limit.
+func NewStationTable(bits int, loadFactor float64, maxProbe int) *StationTable {
+	if loadFactor <= 0 {
+		loadFactor = DefaultLoadFactor
+	}
+	if maxProbe <= 0 {
+		// The probing always stops at an empty slot, see resize.
+		maxProbe = math.MaxInt
+	}
+	t := &StationTable{
+		names:      make([]string, 0, MaxStations),
+		hashes:     make([]uint32, 0, MaxStations),
+		loadFactor: loadFactor,
+		maxProbe:   maxProbe,
+	}
+	t.resize(1 << bits)
+	return t
+}
+
+// Len returns the number of stations.
+func (t *StationTable) Len() int {
+	return len(t.names)
+}
+
+// Size returns the number of slots.
+func (t *StationTable) Size() int {
+	return len(t.slots)
+}
+
+// Name returns the name of the station with index `idx`.
+func (t *StationTable) Name(idx int) string {
+	return t.names[idx]
+}
+
+// Hash returns the hash of the station with index `idx`.
+func (t *StationTable) Hash(idx int) uint32 {
+	return t.hashes[idx]
+}
+
+// Overflow returns the number of stations in the Go map, because their probe
+// sequence was longer than the maximum probe length.
+func (t *StationTable) Overflow() int {
+	return len(t.overflow)
+}
+
+// Index returns the index of the station `name` with the hash `hash`. A new
+// station is added with the next index and `isNew` is true.
+func (t *StationTable) Index(name []byte, hash uint32) (idx int, isNew bool) {
+	// Wrap around at the end of the table, else stations hashing near its end
+	// get lost. There is always an empty slot, see resize.
+	i := hash & t.mask
+	for probe := 0; probe < t.maxProbe; probe++ {
+		slot := t.slots[i]
+		if slot.idx == 0 {
+			return t.add(i, string(name), hash), true
+		}
+		if slot.hash == hash && t.names[slot.idx-1] == string(name) {
+			return int(slot.idx - 1), false
+		}
+		i = (i + 1) & t.mask
+	}
+	return t.indexOverflow(string(name), hash)
+}
+
+// limitsProbes returns true, if the table has a maximum probe length.
+func (t *StationTable) limitsProbes() bool {
+	return t.maxProbe != math.MaxInt
+}
+
+// IndexString is Index of a string.
+func (t *StationTable) IndexString(name string, hash uint32) (idx int, isNew bool) {
+	i := hash & t.mask
+	for probe := 0; probe < t.maxProbe; probe++ {
+		slot := t.slots[i]
+		if slot.idx == 0 {
+			return t.add(i, name, hash), true
+		}
+		if slot.hash == hash && t.names[slot.idx-1] == name {
+			return int(slot.idx - 1), false
+		}
+		i = (i + 1) & t.mask
+	}
+	return t.indexOverflow(name, hash)
+}
+
+// indexOverflow is Index of a station, whose first `maxProbe` slots are all
+// used by other stations. As slots are never emptied, it can't be in the
+// table itself.
+func (t *StationTable) indexOverflow(name string, hash uint32) (idx int, isNew bool) {
+	if idx, ok := t.overflow[name]; ok {
+		return idx, false
+	}
+	return t.add(noSlot, name, hash), true
+}
+
+// noSlot is the slot of stations added to the Go map.
+const noSlot = math.MaxUint32
+
+// add adds the new station `name` to the empty slot `slotIdx`, or to the Go
+// map if it is noSlot, and returns its index. If the table gets too full, it
+// grows.
+func (t *StationTable) add(slotIdx uint32, name string, hash uint32) int {
+	idx := len(t.names)
+	t.names = append(t.names, name)
+	t.hashes = append(t.hashes, hash)
+	if len(t.names) <= t.maxLen {
+		t.insert(slotIdx, idx)
+		return idx
+	}
+	// Growing adds all stations again, the new one too.
+	for len(t.names) > t.maxLen {
+		t.resize(2 * len(t.slots))
+	}
+	return idx
+}
+
+// insert adds the station with index `idx` to the slot `slotIdx`, or to the
+// Go map if it is noSlot.
+func (t *StationTable) insert(slotIdx uint32, idx int) {
+	if slotIdx == noSlot {
+		if t.overflow == nil {
+			t.overflow = make(map[string]int)
+		}
+		t.overflow[t.names[idx]] = idx
+		return
+	}
+	t.slots[slotIdx] = tableSlot{hash: t.hashes[idx], idx: uint32(idx) + 1}
+}
+
+// resize changes the number of slots to `size`, a power of 2, and adds all
+// stations again, in the order of their indices.
+func (t *StationTable) resize(size int) {
+	t.slots = make([]tableSlot, size)
+	t.mask = uint32(size - 1)
+	t.overflow = nil
+	// Keep at least one slot empty, so the probing always stops.
+	t.maxLen = min(int(t.loadFactor*float64(size)), size-1)
+	for idx, hash := range t.hashes {
+		slotIdx := uint32(noSlot)
+		i := hash & t.mask
+		for probe := 0; probe < t.maxProbe; probe++ {
+			if t.slots[i].idx == 0 {
+				slotIdx = i
+				break
+			}
+			i = (i + 1) & t.mask
+		}
+		t.insert(slotIdx, idx)
+	}
+}
@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     onebrc/table_test.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package onebrc_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+)
+
+// TestStationTableWrap adds stations hashing to the last slot, which must
+// wrap around to the start of the table.
+func TestStationTableWrap(t *testing.T) {
+	table := onebrc.NewStationTable(4, 0.75, 0)
+	for idx := range 10 {
+		name := fmt.Sprintf("station %d", idx)
+		stIdx, isNew := table.IndexString(name, 15)
+		if !isNew || stIdx != idx {
+			t.Fatalf("%s: got index %d, new %t, want %d, true", name, stIdx, isNew, idx)
+		}
+	}
+	for idx := range 10 {
+		name := fmt.Sprintf("station %d", idx)
+		stIdx, isNew := table.Index([]byte(name), 15)
+		if isNew || stIdx != idx {
+			t.Fatalf("%s: got index %d, new %t, want %d, false", name, stIdx, isNew, idx)
+		}
+	}
+	if table.Size() != 16 {
+		t.Errorf("got %d slots, want 16", table.Size())
+	}
+}
+
+// TestStationTableGrow checks that the table doubles its size when more than
+// the load factor of the slots are used, and keeps all stations.
+func TestStationTableGrow(t *testing.T) {
+	for _, tc := range []struct {
+		loadFactor float64
+		stations   int
+		size       int
+	}{
+		{loadFactor: 0.5, stations: 8, size: 16},
+		{loadFactor: 0.5, stations: 9, size: 32},
+		{loadFactor: 0.5, stations: 1000, size: 2048},
+		// At least one slot is always empty.
+		{loadFactor: 1, stations: 16, size: 32},
+		{loadFactor: 0, stations: 9, size: 32},
+	} {
+		table := onebrc.NewStationTable(4, tc.loadFactor, 0)
+		for idx := range tc.stations {
+			// Bad hashes, to get long probe sequences.
+			table.IndexString(fmt.Sprintf("station %d", idx), uint32(idx%5))
+		}
+		if table.Len() != tc.stations || table.Size() != tc.size {
+			t.Errorf("load factor %g: got %d stations, %d slots, want %d, %d",
+				tc.loadFactor, table.Len(), table.Size(), tc.stations, tc.size)
+		}
+		for idx := range tc.stations {
+			name := fmt.Sprintf("station %d", idx)
+			stIdx, isNew := table.IndexString(name, uint32(idx%5))
+			if isNew || stIdx != idx || table.Name(stIdx) != name {
+				t.Fatalf("load factor %g, %s: got index %d, new %t, want %d, false",
+					tc.loadFactor, name, stIdx, isNew, idx)
+			}
+		}
+	}
+}
+
+// TestStationTableMaxProbe adds stations with the same hash, which must end up
+// in the Go map after the first `maxProbe` of them, also after growing.
+func TestStationTableMaxProbe(t *testing.T) {
+	table := onebrc.NewStationTable(4, 0.5, 3)
+	for idx := range 100 {
+		name := fmt.Sprintf("station %d", idx)
+		stIdx, isNew := table.Index([]byte(name), 7)
+		if !isNew || stIdx != idx {
+			t.Fatalf("%s: got index %d, new %t, want %d, true", name, stIdx, isNew, idx)
+		}
+	}
+	// 100 stations and a load factor of 0.5.
+	if table.Size() != 256 || table.Overflow() != 97 {
+		t.Errorf("got %d slots, %d stations in the map, want 256, 97", table.Size(), table.Overflow())
+	}
+	for idx := range 100 {
+		name := fmt.Sprintf("station %d", idx)
+		stIdx, isNew := table.IndexString(name, 7)
+		if isNew || stIdx != idx || table.Name(stIdx) != name {
+			t.Fatalf("%s: got index %d, new %t, want %d, false", name, stIdx, isNew, idx)
+		}
+	}
+}
+
+// TestStationTableStats checks the statistics of tables with known slots.
+func TestStationTableStats(t *testing.T) {
+	// Slots 6, 7 and 0 are a single run, wrapping around.
+	table := onebrc.NewStationTable(3, 1, 0)
+	for _, station := range []struct {
+		name string
+		hash uint32
+	}{{"A", 6}, {"B", 6}, {"C", 7}, {"D", 2}} {
+		table.IndexString(station.name, station.hash)
+	}
+	stats := table.Stats([]uint{1, 2, 3, 4})
+	if stats.Stations != 4 || stats.Slots != 8 || stats.FillRatio() != 0.5 || stats.Overflow != 0 {
+		t.Errorf("got %d stations, %d slots, fill %g, overflow %d, want 4, 8, 0.5, 0",
+			stats.Stations, stats.Slots, stats.FillRatio(), stats.Overflow)
+	}
+	// B and C are one slot after the slot of their hash.
+	if stats.Colliding != 2 || stats.Collisions != 2+3 || stats.MaxProbe() != 1 || stats.MaxChain != 3 {
+		t.Errorf("got %d colliding, %d collisions, max probe %d, max chain %d, want 2, 5, 1, 3",
+			stats.Colliding, stats.Collisions, stats.MaxProbe(), stats.MaxChain)
+	}
+	if fmt.Sprint(stats.ProbeLengths) != "[2 2]" {
+		t.Errorf("got probe lengths %v, want [2 2]", stats.ProbeLengths)
+	}
+
+	// Only A is in the table, B and C in the Go map.
+	table = onebrc.NewStationTable(3, 1, 1)
+	for _, name := range []string{"A", "B", "C"} {
+		table.IndexString(name, 0)
+	}
+	stats = table.Stats(nil)
+	if stats.Overflow != 2 || stats.Colliding != 2 || stats.Collisions != 2 || stats.MaxChain != 1 ||
+		fmt.Sprint(stats.ProbeLengths) != "[1]" {
+		t.Errorf("got overflow %d, %d colliding, %d collisions, max chain %d, probe lengths %v, want 2, 2, 2, 1, [1]",
+			stats.Overflow, stats.Colliding, stats.Collisions, stats.MaxChain, stats.ProbeLengths)
+	}
+}
+
+// TestTableStatsReport checks the statistics of the tables in the report.
+func TestTableStatsReport(t *testing.T) {
+	content := []byte("A;1.0\nB;2.0\nA;3.0\nC;4.0\n")
+	report := onebrc.Report{}
+	results, err := onebrc.AggregateBytes(content, onebrc.Options{NumWorkers: 2, TableStats: true, Report: &report})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.WorkerTables) == 0 || report.MergedTable.Stations != len(results) {
+		t.Errorf("got %d worker tables, %d stations in the merged table, want some, %d",
+			len(report.WorkerTables), report.MergedTable.Stations, len(results))
+	}
+	workerStations := 0
+	for _, stats := range report.WorkerTables {
+		workerStations += stats.Stations
+	}
+	if workerStations < len(results) {
+		t.Errorf("got %d stations in the worker tables, want at least %d", workerStations, len(results))
+	}
+}
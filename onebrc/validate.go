@@ -0,0 +1,271 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     onebrc/validate.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package onebrc
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Mode selects how the lines of the data are checked.
+type Mode int
+
+const (
+	// ModeFast does not check the data at all, like go_parallel_eq.go. Invalid
+	// data either panics or yields wrong results.
+	ModeFast Mode = iota
+	// ModeStrict checks every line against the rules of the challenge and
+	// stops at the first invalid line, returning a *ValidationError.
+	ModeStrict
+	// ModeLenient checks every line against the rules of the challenge and
+	// skips invalid lines. The skipped lines are counted per Reason in
+	// Report.Rejects and written to Options.RejectWriter.
+	ModeLenient
+)
+
+// Reason is the reason a line is invalid.
+type Reason int
+
+const (
+	reasonNone Reason = iota
+	// ReasonMissingSemicolon is a line without a semicolon.
+	ReasonMissingSemicolon
+	// ReasonEmptyName is a line with an empty station name.
+	ReasonEmptyName
+	// ReasonNameTooLong is a station name longer than MaxNameLength bytes.
+	ReasonNameTooLong
+	// ReasonNameNotUTF8 is a station name which is not valid UTF-8.
+	ReasonNameNotUTF8
+	// ReasonExtraSemicolon is a line with more than one semicolon.
+	ReasonExtraSemicolon
+	// ReasonEmptyTemperature is a line without a temperature.
+	ReasonEmptyTemperature
+	// ReasonNonNumeric is a temperature containing other characters than
+	// digits, `-` and `.`.
+	ReasonNonNumeric
+	// ReasonTemperatureFormat is a temperature which does not have one or two
+	// integer digits and exactly one fractional digit.
+	ReasonTemperatureFormat
+	// ReasonTemperatureRange is a temperature not in [-99.9, 99.9].
+	ReasonTemperatureRange
+	numReasons
+)
+
+// Reasons returns all reasons a line can be invalid.
+func Reasons() []Reason {
+	reasons := make([]Reason, 0, numReasons-1)
+	for r := reasonNone + 1; r < numReasons; r++ {
+		reasons = append(reasons, r)
+	}
+	return reasons
+}
+
+func (r Reason) String() string {
+	switch r {
+	case reasonNone:
+		return "valid"
+	case ReasonMissingSemicolon:
+		return "missing ';' between station name and temperature"
+	case ReasonEmptyName:
+		return "empty station name"
+	case ReasonNameTooLong:
+		return fmt.Sprintf("station name longer than %d bytes", MaxNameLength)
+	case ReasonNameNotUTF8:
+		return "station name is not valid UTF-8"
+	case ReasonExtraSemicolon:
+		return "more than one ';' in the line"
+	case ReasonEmptyTemperature:
+		return "empty temperature"
+	case ReasonNonNumeric:
+		return "temperature contains non-numeric characters"
+	case ReasonTemperatureFormat:
+		return "temperature does not have one or two integer digits and exactly one fractional digit"
+	case ReasonTemperatureRange:
+		return "temperature not in [-99.9, 99.9]"
+	default:
+		return fmt.Sprintf("unknown reason %d", int(r))
+	}
+}
+
+// ValidationError is the error returned by ModeStrict for an invalid line.
+type ValidationError struct {
+	// Line is the line number, starting at 1.
+	Line int64
+	// Offset is the byte offset of the start of the line, starting at 0.
+	Offset int64
+	// Reason is the reason the line is invalid.
+	Reason Reason
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid line %d at byte offset %d: %s", e.Line, e.Offset, e.Reason)
+}
+
+// RejectCounts is the number of lines skipped by ModeLenient per Reason.
+type RejectCounts [numReasons]int64
+
+// Count returns the number of lines skipped because of `reason`.
+func (c *RejectCounts) Count(reason Reason) int64 {
+	if reason <= reasonNone || reason >= numReasons {
+		return 0
+	}
+	return c[reason]
+}
+
+// Total returns the number of all skipped lines.
+func (c *RejectCounts) Total() int64 {
+	var total int64 = 0
+	for _, count := range c {
+		total += count
+	}
+	return total
+}
+
+func (c *RejectCounts) add(other *RejectCounts) {
+	for idx, count := range other {
+		c[idx] += count
+	}
+}
+
+// checkLine checks `line` - without the newline - against the rules of the
+// challenge.
+// Returns the length of the station name, the temperature in tenths of a
+// degree and the reason the line is invalid, which is `reasonNone` for a
+// valid line.
+func checkLine(line []byte) (int, int, Reason) {
+	semiColonIdx := bytes.IndexByte(line, ';')
+	switch {
+	case semiColonIdx < 0:
+		return 0, 0, ReasonMissingSemicolon
+	case semiColonIdx == 0:
+		return 0, 0, ReasonEmptyName
+	case semiColonIdx > MaxNameLength:
+		return 0, 0, ReasonNameTooLong
+	case !utf8.Valid(line[:semiColonIdx]):
+		return 0, 0, ReasonNameNotUTF8
+	}
+
+	temperature, reason := checkTemperature(line[semiColonIdx+1:])
+	return semiColonIdx, temperature, reason
+}
+
+// checkTemperature parses the temperature `content`, which must be of the
+// form `-?[0-9]{1,2}\.[0-9]`.
+func checkTemperature(content []byte) (int, Reason) {
+	if len(content) == 0 {
+		return 0, ReasonEmptyTemperature
+	}
+	if bytes.IndexByte(content, ';') >= 0 {
+		return 0, ReasonExtraSemicolon
+	}
+
+	negate := 1
+	if content[0] == '-' {
+		negate = -1
+		content = content[1:]
+	}
+
+	temperature := 0
+	intDigits := 0
+	dotIdx := -1
+	for idx, currByte := range content {
+		switch {
+		case currByte >= '0' && currByte <= '9':
+			temperature = min(temperature*10+int(currByte-'0'), 10_000)
+			if dotIdx < 0 {
+				intDigits++
+			}
+		case currByte == '.' && dotIdx < 0:
+			dotIdx = idx
+		case currByte == '.' || currByte == '-':
+			return 0, ReasonTemperatureFormat
+		default:
+			return 0, ReasonNonNumeric
+		}
+	}
+
+	if intDigits < 1 || dotIdx < 0 || dotIdx != len(content)-2 {
+		return 0, ReasonTemperatureFormat
+	}
+	if temperature > 999 {
+		return 0, ReasonTemperatureRange
+	}
+	if intDigits > 2 {
+		return 0, ReasonTemperatureFormat
+	}
+	return negate * temperature, reasonNone
+}
+
+// processChunkChecked is processChunk checking every line of `content`, which
+// starts at the byte offset `offset` of the data.
+// In ModeStrict, the line number of an error is relative to the start of the
+// chunk and is corrected by sumResults. In ModeLenient, the invalid lines are
+// counted and returned, if `opts.RejectWriter` is set.
+func processChunkChecked(content []byte, offset int64, opts Options, channel chan resultType) {
+	stationData := NewStationTemperatures(MaxStations)
+	table := opts.newTable()
+	hasher := opts.hasher()
+
+	var rejects RejectCounts
+	var rejectedLines [][]byte
+	var lines int64 = 0
+	for len(content) > 0 {
+		// Every chunk ends with a newline.
+		newlineIdx := bytes.IndexByte(content, '\n')
+		lines++
+
+		nameLen, temperature, reason := checkLine(content[:newlineIdx])
+		switch {
+		case reason == reasonNone:
+			addStation(table, hasher, &stationData, content[:nameLen], temperature)
+		case opts.Mode == ModeStrict:
+			channel <- resultType{
+				Lines: lines,
+				Err:   &ValidationError{Line: lines, Offset: offset, Reason: reason},
+			}
+			return
+		default:
+			rejects[reason]++
+			if opts.RejectWriter != nil {
+				rejectedLines = append(rejectedLines, content[:newlineIdx])
+			}
+		}
+
+		content = content[newlineIdx+1:]
+		offset += int64(newlineIdx) + 1
+	}
+	channel <- resultType{
+		Temps:         stationData,
+		Table:         table,
+		Lines:         lines,
+		Rejects:       rejects,
+		RejectedLines: rejectedLines,
+		TableStats:    opts.tableStats(table, stationData.Count),
+	}
+}
+
+// addStation adds the temperature `temperature` of the station `station` to
+// `stationData`.
+func addStation(table *StationTable, hasher Hasher, stationData *StationTemperatures, station []byte, temperature int) {
+	stIdx, isNew := table.Index(station, hasher.Hash(station))
+	if isNew {
+		stationData.Reserve(stIdx)
+		stationData.TempSum[stIdx] = temperature
+		stationData.Count[stIdx] = 1
+		stationData.Min[stIdx] = temperature
+		stationData.Max[stIdx] = temperature
+		return
+	}
+	stationData.TempSum[stIdx] += temperature
+	stationData.Count[stIdx]++
+	stationData.Min[stIdx] = min(stationData.Min[stIdx], temperature)
+	stationData.Max[stIdx] = max(stationData.Max[stIdx], temperature)
+}
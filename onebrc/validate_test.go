@@ -0,0 +1,243 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     onebrc/validate_test.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package onebrc_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+)
+
+// The reasons of the invalid lines, reasonValid is a valid line.
+const reasonValid onebrc.Reason = 0
+
+var lineTestCases = []struct {
+	line   string
+	reason onebrc.Reason
+}{
+	{"Hamburg;12.0", reasonValid},
+	{"Hamburg;-12.0", reasonValid},
+	{"Hamburg;1.0", reasonValid},
+	{"Hamburg;-0.0", reasonValid},
+	{"Hamburg;99.9", reasonValid},
+	{"Hamburg;-99.9", reasonValid},
+	{"St. John's;0.5", reasonValid},
+	{"Ürümqi;7.4", reasonValid},
+	{strings.Repeat("a", onebrc.MaxNameLength) + ";1.0", reasonValid},
+	{strings.Repeat("ä", onebrc.MaxNameLength/2) + ";1.0", reasonValid},
+
+	{"Hamburg12.0", onebrc.ReasonMissingSemicolon},
+	{"", onebrc.ReasonMissingSemicolon},
+	{";12.0", onebrc.ReasonEmptyName},
+	{strings.Repeat("a", onebrc.MaxNameLength+1) + ";1.0", onebrc.ReasonNameTooLong},
+	{"\xff\xfe;1.0", onebrc.ReasonNameNotUTF8},
+	{"Hamburg;1.0;2", onebrc.ReasonExtraSemicolon},
+	{"Hamburg;;1.0", onebrc.ReasonExtraSemicolon},
+	{"Hamburg;", onebrc.ReasonEmptyTemperature},
+	{"Hamburg;1a.0", onebrc.ReasonNonNumeric},
+	{"Hamburg;1.0 ", onebrc.ReasonNonNumeric},
+	{"Hamburg;+1.0", onebrc.ReasonNonNumeric},
+	{"Hamburg;1.", onebrc.ReasonTemperatureFormat},
+	{"Hamburg;.5", onebrc.ReasonTemperatureFormat},
+	{"Hamburg;--1.0", onebrc.ReasonTemperatureFormat},
+	{"Hamburg;1.00", onebrc.ReasonTemperatureFormat},
+	{"Hamburg;12", onebrc.ReasonTemperatureFormat},
+	{"Hamburg;1.0.0", onebrc.ReasonTemperatureFormat},
+	{"Hamburg;-", onebrc.ReasonTemperatureFormat},
+	{"Hamburg;100.0", onebrc.ReasonTemperatureRange},
+	{"Hamburg;-100.0", onebrc.ReasonTemperatureRange},
+	{"Hamburg;1000000.0", onebrc.ReasonTemperatureRange},
+}
+
+// TestCheckLine checks single lines in ModeStrict, every Reason has at least
+// one test case.
+func TestCheckLine(t *testing.T) {
+	tested := map[onebrc.Reason]bool{}
+	for _, tc := range lineTestCases {
+		tested[tc.reason] = true
+		_, err := onebrc.AggregateBytes([]byte(tc.line+"\n"), onebrc.Options{Mode: onebrc.ModeStrict})
+		if tc.reason == reasonValid {
+			if err != nil {
+				t.Errorf("line %q: got error %v, want valid", tc.line, err)
+			}
+			continue
+		}
+		var validationErr *onebrc.ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("line %q: got error %v, want %s", tc.line, err, tc.reason)
+			continue
+		}
+		if validationErr.Reason != tc.reason || validationErr.Line != 1 || validationErr.Offset != 0 {
+			t.Errorf("line %q: got %s in line %d at offset %d, want %s in line 1 at offset 0",
+				tc.line, validationErr.Reason, validationErr.Line, validationErr.Offset, tc.reason)
+		}
+	}
+	for _, reason := range onebrc.Reasons() {
+		if !tested[reason] {
+			t.Errorf("no test case of %s", reason)
+		}
+	}
+}
+
+// invalidData returns `numLines` valid lines with the invalid line `invalid`
+// as line number `invalidLine`, and the byte offset of the invalid line.
+func invalidData(numLines int, invalidLine int, invalid string) ([]byte, int64) {
+	var buffer bytes.Buffer
+	var offset int64
+	for line := 1; line <= numLines; line++ {
+		if line == invalidLine {
+			offset = int64(buffer.Len())
+			buffer.WriteString(invalid + "\n")
+			continue
+		}
+		fmt.Fprintf(&buffer, "Station %d;%d.%d\n", line%100, line%199-99, line%10)
+	}
+	return buffer.Bytes(), offset
+}
+
+// gzipData returns `content` compressed as gzip, split into `numMembers`
+// members.
+func gzipData(t *testing.T, content []byte, numMembers int) []byte {
+	t.Helper()
+	var buffer bytes.Buffer
+	partSize := len(content)/numMembers + 1
+	for len(content) > 0 {
+		part := content[:min(partSize, len(content))]
+		content = content[len(part):]
+		gz := gzip.NewWriter(&buffer)
+		_, err := gz.Write(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = gz.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buffer.Bytes()
+}
+
+// TestValidationErrorPosition checks the line number and byte offset of an
+// invalid line in a later chunk, in a stream and in gzip compressed data.
+func TestValidationErrorPosition(t *testing.T) {
+	const numLines = 20_000
+	for _, invalidLine := range []int{1, 2, 9_999, numLines} {
+		content, offset := invalidData(numLines, invalidLine, "Hamburg;1.")
+		fileName := filepath.Join(t.TempDir(), "measurements.txt.gz")
+		err := os.WriteFile(fileName, gzipData(t, content, 4), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		opts := onebrc.Options{Mode: onebrc.ModeStrict, NumWorkers: 4, BlockSize: 4096}
+		for _, tc := range []struct {
+			name      string
+			aggregate func() (onebrc.Results, error)
+		}{
+			{"bytes", func() (onebrc.Results, error) { return onebrc.AggregateBytes(content, opts) }},
+			{"stream", func() (onebrc.Results, error) { return onebrc.AggregateReader(bytes.NewReader(content), opts) }},
+			{"gzip stream", func() (onebrc.Results, error) {
+				return onebrc.AggregateReader(bytes.NewReader(gzipData(t, content, 1)), opts)
+			}},
+			{"gzip members", func() (onebrc.Results, error) { return onebrc.Aggregate(fileName, opts) }},
+		} {
+			t.Run(fmt.Sprintf("%s/line %d", tc.name, invalidLine), func(t *testing.T) {
+				_, err := tc.aggregate()
+				var validationErr *onebrc.ValidationError
+				if !errors.As(err, &validationErr) {
+					t.Fatalf("got error %v, want a validation error", err)
+				}
+				want := onebrc.ValidationError{
+					Line: int64(invalidLine), Offset: offset, Reason: onebrc.ReasonTemperatureFormat,
+				}
+				if *validationErr != want {
+					t.Errorf("got %v, want %v", validationErr, &want)
+				}
+			})
+		}
+	}
+}
+
+// TestLenient checks the reject counts merged from all chunks or blocks, the
+// rejected lines written in the order of the data and the results of the
+// valid lines.
+func TestLenient(t *testing.T) {
+	var content, valid, rejected bytes.Buffer
+	var wantRejects onebrc.RejectCounts
+	lines := 0
+	for idx := range 5_000 {
+		line := fmt.Sprintf("Station %d;%d.%d\n", idx%100, idx%199-99, idx%10)
+		valid.WriteString(line)
+		content.WriteString(line)
+		lines++
+		// An invalid line every 7 lines, cycling through all test cases.
+		if idx%7 == 0 {
+			tc := lineTestCases[(idx/7)%len(lineTestCases)]
+			if tc.reason == reasonValid {
+				continue
+			}
+			content.WriteString(tc.line + "\n")
+			rejected.WriteString(tc.line + "\n")
+			wantRejects[tc.reason]++
+			lines++
+		}
+	}
+	expected, err := onebrc.AggregateBytes(valid.Bytes(), onebrc.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name      string
+		aggregate func(opts onebrc.Options) (onebrc.Results, error)
+	}{
+		{"bytes", func(opts onebrc.Options) (onebrc.Results, error) {
+			return onebrc.AggregateBytes(content.Bytes(), opts)
+		}},
+		{"stream", func(opts onebrc.Options) (onebrc.Results, error) {
+			return onebrc.AggregateReader(bytes.NewReader(content.Bytes()), opts)
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var rejectWriter bytes.Buffer
+			report := onebrc.Report{}
+			results, err := tc.aggregate(onebrc.Options{
+				Mode: onebrc.ModeLenient, NumWorkers: 5, BlockSize: 4096, RejectWriter: &rejectWriter, Report: &report,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Lines != int64(lines) {
+				t.Errorf("got %d lines, want %d", report.Lines, lines)
+			}
+			for _, reason := range onebrc.Reasons() {
+				if report.Rejects.Count(reason) != wantRejects[reason] {
+					t.Errorf("%s: got %d rejects, want %d", reason, report.Rejects.Count(reason), wantRejects[reason])
+				}
+			}
+			if report.Rejects.Total() != wantRejects.Total() {
+				t.Errorf("got %d rejects, want %d", report.Rejects.Total(), wantRejects.Total())
+			}
+			if rejectWriter.String() != rejected.String() {
+				t.Errorf("got rejected lines\n%.200q\nwant\n%.200q", rejectWriter.String(), rejected.String())
+			}
+			for _, difference := range onebrc.Compare(expected.Summaries(), results.Summaries(), 0) {
+				t.Error(difference)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     reference/reference.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+// Package reference is a deliberately simple and slow solution of the
+// challenge, to check the fast ones against.
+//
+// All calculations use exact rational numbers of math/big, the mean is rounded
+// like the Java reference implementation does: to the nearest tenth, ties are
+// rounded towards positive infinity, so `-0.25` is `-0.2` and `0.25` is `0.3`.
+package reference
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+)
+
+// station is the data of a single weather station.
+type station struct {
+	min   *big.Rat
+	max   *big.Rat
+	sum   *big.Rat
+	count int64
+}
+
+// SolveFile returns the results of the measurements file `fileName`, see
+// Solve.
+func SolveFile(fileName string) (onebrc.Summaries, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file '%s': %w", fileName, err)
+	}
+	defer file.Close()
+
+	return Solve(file)
+}
+
+// Solve returns the results of the measurements read from `r`, sorted by
+// station name. Each line must be `name;temperature`, the last line may miss
+// the newline.
+func Solve(r io.Reader) (onebrc.Summaries, error) {
+	stations := make(map[string]*station)
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		name, tempStr, found := strings.Cut(scanner.Text(), ";")
+		temperature, ok := new(big.Rat).SetString(tempStr)
+		if !found || !ok {
+			return nil, fmt.Errorf("invalid line %d: '%s'", lineNum, scanner.Text())
+		}
+
+		data, ok := stations[name]
+		if !ok {
+			data = &station{
+				min: new(big.Rat).Set(temperature),
+				max: new(big.Rat).Set(temperature),
+				sum: new(big.Rat),
+			}
+			stations[name] = data
+		}
+		if temperature.Cmp(data.min) < 0 {
+			data.min.Set(temperature)
+		}
+		if temperature.Cmp(data.max) > 0 {
+			data.max.Set(temperature)
+		}
+		data.sum.Add(data.sum, temperature)
+		data.count++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading line %d: %w", lineNum+1, err)
+	}
+
+	summaries := make(onebrc.Summaries, 0, len(stations))
+	for name, data := range stations {
+		mean := new(big.Rat).Quo(data.sum, new(big.Rat).SetInt64(data.count))
+		summaries = append(summaries, onebrc.Summary{
+			Name:     name,
+			Min:      int(RoundTenths(data.min)),
+			Mean:     int(RoundTenths(mean)),
+			Max:      int(RoundTenths(data.max)),
+			HasCount: true,
+			Count:    uint(data.count),
+			Sum:      int(RoundTenths(data.sum)),
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Name < summaries[j].Name
+	})
+	return summaries, nil
+}
+
+// RoundTenths returns `x` rounded to the nearest tenth, in tenths. Ties are
+// rounded towards positive infinity: `floor(10 * x + 1/2)`.
+func RoundTenths(x *big.Rat) int64 {
+	tenths := new(big.Rat).Mul(x, big.NewRat(10, 1))
+	tenths.Add(tenths, big.NewRat(1, 2))
+	// The denominator is always positive, so the Euclidean division is the
+	// floor.
+	return new(big.Int).Div(tenths.Num(), tenths.Denom()).Int64()
+}
@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     reference/reference_test.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package reference_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+	"github.com/Release-Candidate/1-billion-row-challenge/reference"
+)
+
+// The means of sums and counts in tenths of degrees, rounded like Java does:
+// ties towards positive infinity.
+var meanCases = []struct {
+	sum   int
+	count uint
+	want  int64
+}{
+	{0, 5, 0},
+	{1, 2, 1},
+	// -0.05 is 0.0, not -0.1 or -0.0.
+	{-1, 2, 0},
+	{3, 2, 2},
+	{-3, 2, -1},
+	{5, 2, 3},
+	{-5, 2, -2},
+	{-7, 2, -3},
+	{25, 10, 3},
+	{-25, 10, -2},
+	{15, 6, 3},
+	{-15, 6, -2},
+	{5, 4, 1},
+	{-5, 4, -1},
+	{-3, 4, -1},
+	{-1, 3, 0},
+	{-2, 3, -1},
+	{1001, 2, 501},
+	{-1001, 2, -500},
+	{1997, 2, 999},
+	{-1997, 2, -998},
+	{999, 1, 999},
+	{-999, 1, -999},
+}
+
+// TestRoundTenths checks RoundTenths and Station.MeanTemp of the onebrc
+// package against the rounding of Java.
+func TestRoundTenths(t *testing.T) {
+	for _, tc := range meanCases {
+		mean := big.NewRat(int64(tc.sum), 10*int64(tc.count))
+		if got := reference.RoundTenths(mean); got != tc.want {
+			t.Errorf("RoundTenths(%d/%d): got %d, want %d", tc.sum, 10*tc.count, got, tc.want)
+		}
+
+		station := onebrc.Station{Sum: tc.sum, Count: tc.count}
+		got := station.MeanTemp()
+		if math.Round(10*got) != float64(tc.want) {
+			t.Errorf("MeanTemp of sum %d and count %d: got %.1f, want %.1f", tc.sum, tc.count, got, float64(tc.want)/10)
+		}
+		if got == 0 && math.Signbit(got) {
+			t.Errorf("MeanTemp of sum %d and count %d: got -0.0, want 0.0", tc.sum, tc.count)
+		}
+	}
+}
+
+// TestSolveMean checks the means of Solve for exact halves.
+func TestSolveMean(t *testing.T) {
+	for _, tc := range meanCases {
+		// `count` temperatures, adding up to `sum`.
+		var data strings.Builder
+		temperatures := make([]int, tc.count)
+		temperatures[0] = tc.sum
+		for idx := 1; idx < len(temperatures) && (temperatures[0] > 999 || temperatures[0] < -999); idx++ {
+			temperatures[idx] = temperatures[0] / 2
+			temperatures[0] -= temperatures[idx]
+		}
+		for _, temperature := range temperatures {
+			sign := ""
+			if temperature < 0 {
+				sign = "-"
+			}
+			abs := max(temperature, -temperature)
+			fmt.Fprintf(&data, "Station;%s%d.%d\n", sign, abs/10, abs%10)
+		}
+
+		summaries, err := reference.Solve(strings.NewReader(data.String()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(summaries) != 1 || int64(summaries[0].Mean) != tc.want {
+			t.Errorf("%q: got %+v, want a mean of %d", data.String(), summaries, tc.want)
+		}
+	}
+}
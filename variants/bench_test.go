@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     variants/bench_test.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package variants_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/generate"
+	"github.com/Release-Candidate/1-billion-row-challenge/variants"
+)
+
+// The environment variable setting the number of rows of the benchmark data,
+// like `ONEBRC_BENCH_ROWS=10_000_000`.
+const benchRowsEnv = "ONEBRC_BENCH_ROWS"
+
+const defaultBenchRows = 1_000_000
+
+// BenchmarkVariants benchmarks all variants on the same generated file.
+func BenchmarkVariants(b *testing.B) {
+	rows := int64(defaultBenchRows)
+	if rowsStr := os.Getenv(benchRowsEnv); rowsStr != "" {
+		var err error
+		// Underscores are allowed, like `1_000_000`.
+		rows, err = strconv.ParseInt(strings.ReplaceAll(rowsStr, "_", ""), 10, 64)
+		if err != nil || rows <= 0 {
+			b.Fatalf("%s must be a positive integer, not '%s'", benchRowsEnv, rowsStr)
+		}
+	}
+
+	fileName := filepath.Join(b.TempDir(), "measurements.txt")
+	_, err := generate.GenerateFile(fileName, generate.Options{Rows: rows, Seed: 1, Stations: stations})
+	if err != nil {
+		b.Fatal(err)
+	}
+	info, err := os.Stat(fileName)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, variant := range variants.All() {
+		b.Run(variant.Name(), func(b *testing.B) {
+			b.SetBytes(info.Size())
+			for range b.N {
+				_, err := variant.Run(fileName)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(rows)*float64(b.N)/b.Elapsed().Seconds(), "rows/s")
+		})
+	}
+}
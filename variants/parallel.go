@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     variants/parallel.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package variants
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+)
+
+type resultType struct {
+	Temps  onebrc.StationTemperatures
+	IdxMap map[string]int
+	Err    error
+}
+
+// parallelConfig holds the differences between the parallel versions which use
+// a map of indices.
+type parallelConfig struct {
+	// The number of chunks is threadFactor * runtime.NumCPU().
+	threadFactor int
+	// The number of goroutines summing the results of the chunks, if 0, the
+	// results are summed in the main goroutine.
+	numSumChans int
+	// Use non-blocking channels with a buffer of 1.
+	bufferedChans bool
+	// The parser of a chunk.
+	processChunk func(content []byte) (onebrc.StationTemperatures, map[string]int)
+}
+
+func parallel(fileName string) (onebrc.Results, error) {
+	return runParallel(fileName, parallelConfig{
+		threadFactor: 1,
+		processChunk: processChunk,
+	})
+}
+
+func parallelThreadFactor(fileName string) (onebrc.Results, error) {
+	return runParallel(fileName, parallelConfig{
+		threadFactor: 2,
+		processChunk: processChunk,
+	})
+}
+
+func parallelII(fileName string) (onebrc.Results, error) {
+	return runParallel(fileName, parallelConfig{
+		threadFactor: 20,
+		numSumChans:  2,
+		processChunk: processChunk,
+	})
+}
+
+func runParallel(fileName string, config parallelConfig) (onebrc.Results, error) {
+	numCPUs := config.threadFactor * runtime.NumCPU()
+
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file '%s': %w", fileName, err)
+	}
+	defer file.Close()
+
+	fsInfo, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error getting data of file '%s': %w", fileName, err)
+	}
+
+	chunkList, err := generateChunkIndices(numCPUs, fsInfo.Size(), file, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	channels := make([]chan resultType, len(chunkList))
+	for idx, chunk := range chunkList {
+		if config.bufferedChans {
+			channels[idx] = make(chan resultType, 1)
+		} else {
+			channels[idx] = make(chan resultType)
+		}
+		go processFileChunk(chunk, file, config.processChunk, channels[idx])
+	}
+
+	if config.numSumChans > 0 {
+		numSumChans := min(config.numSumChans, len(channels))
+		sumChannels := make([]chan resultType, numSumChans)
+		for i := 0; i < numSumChans; i++ {
+			if config.bufferedChans {
+				sumChannels[i] = make(chan resultType, 1)
+			} else {
+				sumChannels[i] = make(chan resultType)
+			}
+			from := i * len(channels) / numSumChans
+			to := (i + 1) * len(channels) / numSumChans
+			go sumResults(channels[from:to], sumChannels[i])
+		}
+		channels = sumChannels
+	}
+
+	total := make(chan resultType, 1)
+	sumResults(channels, total)
+	result := <-total
+	if result.Err != nil {
+		return nil, result.Err
+	}
+
+	return resultsFromMap(result.IdxMap, result.Temps), nil
+}
+
+func sumResults(channels []chan resultType, result chan resultType) {
+	stationSumData := onebrc.NewStationTemperatures(10_000)
+	stationSumIdxMap := make(map[string]int, 10_000)
+
+	var err error
+	stationIdx := 0
+	for _, channel := range channels {
+		result := <-channel
+		// Read all channels, even after an error, to not block any goroutine.
+		if result.Err != nil {
+			err = result.Err
+			continue
+		}
+
+		stationIdx = mergeResults(&stationSumData, stationSumIdxMap, stationIdx, result.Temps, result.IdxMap)
+	}
+
+	result <- resultType{
+		Temps:  stationSumData,
+		IdxMap: stationSumIdxMap,
+		Err:    err,
+	}
+}
+
+func processFileChunk(chunk chunk, file *os.File,
+	processChunk func(content []byte) (onebrc.StationTemperatures, map[string]int),
+	channel chan resultType,
+) {
+	content, err := readChunk(chunk, file)
+	if err != nil {
+		channel <- resultType{Err: err}
+		return
+	}
+
+	stationData, stationIdxMap := processChunk(content)
+	channel <- resultType{Temps: stationData, IdxMap: stationIdxMap}
+}
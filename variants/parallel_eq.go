@@ -0,0 +1,17 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     variants/parallel_eq.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package variants
+
+import "github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+
+// go_parallel_eq.go is the onebrc package.
+func parallelEq(fileName string) (onebrc.Results, error) {
+	return onebrc.Aggregate(fileName, onebrc.Options{})
+}
@@ -0,0 +1,294 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     variants/parallel_fnv.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+// Uses FNV hash algorithm: http://www.isthe.com/chongo/tech/comp/fnv/index.html
+
+package variants
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"syscall"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+)
+
+type mapStruct struct {
+	Station string
+	idx     int
+	// hash is the hash of the station before masking, to move it when the
+	// table grows.
+	hash uint32
+}
+
+type fnvResultType struct {
+	Temps  onebrc.StationTemperatures
+	IdxMap []mapStruct
+}
+
+const (
+	numBits        = 16
+	mask           = (1 << numBits) - 1
+	fnvPrime       = 16777619
+	fnvOffsetBasis = 2166136261
+)
+
+func fnvHash(s string) uint32 {
+	var hash uint32 = fnvOffsetBasis
+	for _, ch := range s {
+		hash ^= uint32(ch)
+		hash *= fnvPrime
+	}
+	return hash
+}
+
+// growFNV returns the stations of `stationIdxMap` in a table of twice the
+// size. go_parallel_fnv.go has a fixed table of `mask + 1` slots, which hangs
+// if there are more stations than slots. So the table grows if more than half
+// of the slots are used, which never happens with valid data.
+func growFNV(stationIdxMap []mapStruct) []mapStruct {
+	grown := make([]mapStruct, 2*len(stationIdxMap))
+	tableMask := uint32(len(grown) - 1)
+	for _, station := range stationIdxMap {
+		if station.Station == "" {
+			continue
+		}
+		for i := station.hash & tableMask; ; i = (i + 1) & tableMask {
+			if grown[i].Station == "" {
+				grown[i] = station
+				break
+			}
+		}
+	}
+	return grown
+}
+
+// parallelFNV is go_parallel_fnv.go, except that its hash tables wrap around
+// and grow beyond 2^16 slots, see growFNV.
+func parallelFNV(fileName string) (onebrc.Results, error) {
+	return parallelMmap(fileName, processChunkFNV, sumResultsFNV, func(result fnvResultType) onebrc.Results {
+		results := make(onebrc.Results, 0, 10_000)
+		for _, station := range result.IdxMap {
+			if station.Station == "" {
+				continue
+			}
+			idx := station.idx
+			results = append(results, onebrc.Station{
+				Name:  station.Station,
+				Min:   result.Temps.Min[idx],
+				Max:   result.Temps.Max[idx],
+				Sum:   result.Temps.TempSum[idx],
+				Count: result.Temps.Count[idx],
+			})
+		}
+		return results
+	})
+}
+
+// parallelMmap maps the file `fileName` into memory, processes its chunks in
+// parallel using `processChunk` and sums their results using `sumResults`.
+// Returns the sorted results of the sum, returned by `stations`. Only the hash
+// tables of parallel-fnv and parallel-table differ.
+func parallelMmap[R any](fileName string, processChunk func(content []byte, channel chan R),
+	sumResults func(channels []chan R, result chan R), stations func(result R) onebrc.Results,
+) (results onebrc.Results, err error) {
+	numCPUs := 10 * runtime.NumCPU()
+
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file '%s': %w", fileName, err)
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error getting data of file '%s': %w", fileName, err)
+	}
+
+	size := stat.Size()
+	// Mmap does not like empty files.
+	if size == 0 {
+		return onebrc.Results{}, nil
+	}
+
+	content, err := syscall.Mmap(int(file.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, fmt.Errorf("error mapping file '%s': %w", fileName, err)
+	}
+	defer func() {
+		unmapErr := syscall.Munmap(content)
+		if unmapErr != nil && err == nil {
+			err = fmt.Errorf("error unmapping file '%s': %w", fileName, unmapErr)
+		}
+	}()
+
+	chunkList, err := generateChunkIndices(numCPUs, size, file, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	channels := make([]chan R, len(chunkList))
+	for idx, chunk := range chunkList {
+		chunkContent := content[chunk.StartIdx : chunk.EndIdx+1]
+		// The mapped file can't be changed, so copy the last chunk if the
+		// newline at the end is missing.
+		if chunkContent[len(chunkContent)-1] != '\n' {
+			chunkContent = append(append(make([]byte, 0, len(chunkContent)+1), chunkContent...), '\n')
+		}
+		// non-blocking channels
+		channels[idx] = make(chan R, 1)
+		go processChunk(chunkContent, channels[idx])
+	}
+
+	numSumChans := min(2, len(channels))
+	sumChannels := make([]chan R, numSumChans)
+	for i := 0; i < numSumChans; i++ {
+		sumChannels[i] = make(chan R, 1)
+		from := i * len(channels) / numSumChans
+		to := (i + 1) * len(channels) / numSumChans
+		go sumResults(channels[from:to], sumChannels[i])
+	}
+
+	total := make(chan R, 1)
+	sumResults(sumChannels, total)
+
+	results = stations(<-total)
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+
+	return results, nil
+}
+
+func sumResultsFNV(channels []chan fnvResultType, result chan fnvResultType) {
+	stationSumData := onebrc.NewStationTemperatures(10_000)
+	stationSumIdxMap := make([]mapStruct, mask+1)
+	var tableMask uint32 = mask
+
+	stationIdx := 0
+	for _, channel := range channels {
+		result := <-channel
+		stationData := result.Temps
+		stationIdxMap := result.IdxMap
+
+		for _, station := range stationIdxMap {
+			if station.Station == "" {
+				continue
+			}
+			nameHash := fnvHash(station.Station)
+			idx := station.idx
+			// Wrap around at the end of the table, else stations hashing near its
+			// end get lost.
+			for i := nameHash & tableMask; ; i = (i + 1) & tableMask {
+				if stationSumIdxMap[i].Station == station.Station {
+					stIdx := stationSumIdxMap[i].idx
+					stationSumData.TempSum[stIdx] += stationData.TempSum[idx]
+					stationSumData.Count[stIdx] += stationData.Count[idx]
+					stationSumData.Min[stIdx] = min(stationData.Min[idx], stationSumData.Min[stIdx])
+					stationSumData.Max[stIdx] = max(stationData.Max[idx], stationSumData.Max[stIdx])
+					break
+				} else if stationSumIdxMap[i].Station == "" {
+					stationSumIdxMap[i].idx = stationIdx
+					stationSumIdxMap[i].Station = station.Station
+					stationSumIdxMap[i].hash = nameHash
+					stationSumData.Reserve(stationIdx)
+					stationSumData.TempSum[stationIdx] = stationData.TempSum[idx]
+					stationSumData.Count[stationIdx] = stationData.Count[idx]
+					stationSumData.Min[stationIdx] = stationData.Min[idx]
+					stationSumData.Max[stationIdx] = stationData.Max[idx]
+					stationIdx++
+					if 2*stationIdx > len(stationSumIdxMap) {
+						stationSumIdxMap = growFNV(stationSumIdxMap)
+						tableMask = uint32(len(stationSumIdxMap) - 1)
+					}
+					break
+				}
+			}
+		}
+	}
+
+	result <- fnvResultType{
+		Temps:  stationSumData,
+		IdxMap: stationSumIdxMap,
+	}
+}
+
+func processChunkFNV(content []byte, channel chan fnvResultType) {
+	stationData := onebrc.NewStationTemperatures(10_000)
+	stationIdxMap := make([]mapStruct, mask+1)
+	var tableMask uint32 = mask
+	stationIdx := 0
+
+	station := [100]byte{}
+	// We suppose the file is valid, without a single error.
+	// Not a single error check is made.
+	for len(content) > 0 {
+
+		// Station name is not empty.
+		semiColonIdx := 1
+		station[0] = content[0]
+		currByte := content[1]
+		var nameHash uint32 = fnvOffsetBasis
+		for currByte != ';' {
+			station[semiColonIdx] = currByte
+			nameHash ^= uint32(currByte)
+			nameHash *= fnvPrime
+			semiColonIdx++
+			currByte = content[semiColonIdx]
+		}
+		var temperature int = 0
+		negate := 1
+		if content[semiColonIdx+1] == '-' {
+			negate = -1
+			content = content[semiColonIdx+2:]
+		} else {
+			content = content[semiColonIdx+1:]
+		}
+
+		// Either `N.N\n` or `NN.N\n`
+		if content[1] == '.' {
+			temperature = negate * (int(content[0])*10 + int(content[2]) - 528)
+			content = content[4:]
+		} else {
+			temperature = negate * (int(content[0])*100 + int(content[1])*10 + int(content[3]) - 5328)
+			content = content[5:]
+		}
+
+		// Wrap around at the end of the table, else stations hashing near its
+		// end get lost.
+		for i := nameHash & tableMask; ; i = (i + 1) & tableMask {
+			if stationIdxMap[i].Station == string(station[:semiColonIdx]) {
+				stIdx := stationIdxMap[i].idx
+				stationData.TempSum[stIdx] += temperature
+				stationData.Count[stIdx]++
+				stationData.Min[stIdx] = min(stationData.Min[stIdx], temperature)
+				stationData.Max[stIdx] = max(stationData.Max[stIdx], temperature)
+				break
+			} else if stationIdxMap[i].Station == "" {
+				stationIdxMap[i].Station = string(station[:semiColonIdx])
+				stationIdxMap[i].idx = stationIdx
+				stationIdxMap[i].hash = nameHash
+				stationData.Reserve(stationIdx)
+				stationData.TempSum[stationIdx] = temperature
+				stationData.Count[stationIdx] = 1
+				stationData.Min[stationIdx] = temperature
+				stationData.Max[stationIdx] = temperature
+				stationIdx++
+				if 2*stationIdx > len(stationIdxMap) {
+					stationIdxMap = growFNV(stationIdxMap)
+					tableMask = uint32(len(stationIdxMap) - 1)
+				}
+				break
+			}
+		}
+	}
+	channel <- fnvResultType{Temps: stationData, IdxMap: stationIdxMap}
+}
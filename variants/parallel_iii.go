@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     variants/parallel_iii.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package variants
+
+import "github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+
+func parallelIII(fileName string) (onebrc.Results, error) {
+	return runParallel(fileName, parallelConfig{
+		threadFactor:  20,
+		numSumChans:   2,
+		bufferedChans: true,
+		processChunk:  processChunkIII,
+	})
+}
+
+func processChunkIII(content []byte) (onebrc.StationTemperatures, map[string]int) {
+	stationData := onebrc.NewStationTemperatures(10_000)
+
+	stationIdxMap := make(map[string]int, 10_000)
+	stationIdx := 0
+
+	station := [100]byte{}
+	// We suppose the file is valid, without a single error.
+	// Not a single error check is made.
+	for len(content) > 0 {
+
+		// Station name is not empty.
+		semiColonIdx := 1
+		station[0] = content[0]
+		currByte := content[1]
+		for currByte != ';' {
+			station[semiColonIdx] = currByte
+			semiColonIdx++
+			currByte = content[semiColonIdx]
+		}
+		var temperature int = 0
+		var negate = false
+		if content[semiColonIdx+1] == '-' {
+			negate = true
+			content = content[semiColonIdx+2:]
+		} else {
+			content = content[semiColonIdx+1:]
+		}
+
+		// Either `N.N\n` or `NN.N\n`
+		if content[1] == '.' {
+			if negate {
+				temperature = 528 - int(content[0])*10 - int(content[2])
+			} else {
+				temperature = int(content[0])*10 + int(content[2]) - 528
+			}
+			content = content[4:]
+		} else {
+			if negate {
+				temperature = 5328 - int(content[0])*100 - int(content[1])*10 - int(content[3])
+			} else {
+				temperature = int(content[0])*100 + int(content[1])*10 + int(content[3]) - 5328
+			}
+			content = content[5:]
+		}
+
+		stIdx, ok := stationIdxMap[string(station[:semiColonIdx])]
+		if ok {
+			stationData.TempSum[stIdx] += temperature
+			stationData.Count[stIdx]++
+			stationData.Min[stIdx] = min(stationData.Min[stIdx], temperature)
+			stationData.Max[stIdx] = max(stationData.Max[stIdx], temperature)
+		} else {
+			stationIdxMap[string(station[:semiColonIdx])] = stationIdx
+			stationData.Reserve(stationIdx)
+			stationData.TempSum[stationIdx] = temperature
+			stationData.Count[stationIdx] = 1
+			stationData.Min[stationIdx] = temperature
+			stationData.Max[stationIdx] = temperature
+			stationIdx++
+		}
+	}
+	return stationData, stationIdxMap
+}
@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     variants/parallel_preparation.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package variants
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+)
+
+func parallelPreparation(fileName string) (onebrc.Results, error) {
+	numCPUs := runtime.NumCPU()
+
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file '%s': %w", fileName, err)
+	}
+	defer file.Close()
+
+	fsInfo, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error getting data of file '%s': %w", fileName, err)
+	}
+
+	chunkList, err := generateChunkIndices(numCPUs, fsInfo.Size(), file, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	stationSumData := onebrc.NewStationTemperatures(10_000)
+	stationSumIdxMap := make(map[string]int, 10_000)
+
+	stationIdx := 0
+	for _, chunk := range chunkList {
+		buffer, err := readChunk(chunk, file)
+		if err != nil {
+			return nil, err
+		}
+		stationData, stationIdxMap := processChunk(buffer)
+
+		stationIdx = mergeResults(&stationSumData, stationSumIdxMap, stationIdx, stationData, stationIdxMap)
+	}
+
+	return resultsFromMap(stationSumIdxMap, stationSumData), nil
+}
+
+// mergeResults adds the stations of `stationIdxMap` to `stationSumIdxMap`,
+// new stations get indices starting at `stationIdx`.
+// Returns the index of the next new station.
+func mergeResults(stationSumData *onebrc.StationTemperatures, stationSumIdxMap map[string]int, stationIdx int,
+	stationData onebrc.StationTemperatures, stationIdxMap map[string]int,
+) int {
+	for station, idx := range stationIdxMap {
+		stIdx, ok := stationSumIdxMap[station]
+		if ok {
+			stationSumData.TempSum[stIdx] += stationData.TempSum[idx]
+			stationSumData.Count[stIdx] += stationData.Count[idx]
+			stationSumData.Min[stIdx] = min(stationData.Min[idx], stationSumData.Min[stIdx])
+			stationSumData.Max[stIdx] = max(stationData.Max[idx], stationSumData.Max[stIdx])
+		} else {
+			stationSumIdxMap[station] = stationIdx
+			stationSumData.Reserve(stationIdx)
+			stationSumData.TempSum[stationIdx] = stationData.TempSum[idx]
+			stationSumData.Count[stationIdx] = stationData.Count[idx]
+			stationSumData.Min[stationIdx] = stationData.Min[idx]
+			stationSumData.Max[stationIdx] = stationData.Max[idx]
+			stationIdx++
+		}
+	}
+	return stationIdx
+}
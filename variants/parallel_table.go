@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     variants/parallel_table.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package variants
+
+import "github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+
+type tableResultType struct {
+	Temps onebrc.StationTemperatures
+	Table *onebrc.StationTable
+}
+
+// parallelTable is parallelFNV using the open addressing onebrc.StationTable
+// of the onebrc package instead of its own hash table.
+func parallelTable(fileName string) (onebrc.Results, error) {
+	return parallelMmap(fileName, processChunkTable, sumResultsTable, func(result tableResultType) onebrc.Results {
+		results := make(onebrc.Results, 0, result.Table.Len())
+		for idx := range result.Table.Len() {
+			results = append(results, onebrc.Station{
+				Name:  result.Table.Name(idx),
+				Min:   result.Temps.Min[idx],
+				Max:   result.Temps.Max[idx],
+				Sum:   result.Temps.TempSum[idx],
+				Count: result.Temps.Count[idx],
+			})
+		}
+		return results
+	})
+}
+
+func sumResultsTable(channels []chan tableResultType, result chan tableResultType) {
+	stationSumData := onebrc.NewStationTemperatures(10_000)
+	sumTable := onebrc.NewStationTable(onebrc.DefaultTableBits, onebrc.DefaultLoadFactor, 0)
+
+	for _, channel := range channels {
+		result := <-channel
+		stationData := result.Temps
+		table := result.Table
+
+		for idx := range table.Len() {
+			stIdx, isNew := sumTable.IndexString(table.Name(idx), table.Hash(idx))
+			if isNew {
+				stationSumData.Reserve(stIdx)
+				stationSumData.TempSum[stIdx] = stationData.TempSum[idx]
+				stationSumData.Count[stIdx] = stationData.Count[idx]
+				stationSumData.Min[stIdx] = stationData.Min[idx]
+				stationSumData.Max[stIdx] = stationData.Max[idx]
+				continue
+			}
+			stationSumData.TempSum[stIdx] += stationData.TempSum[idx]
+			stationSumData.Count[stIdx] += stationData.Count[idx]
+			stationSumData.Min[stIdx] = min(stationData.Min[idx], stationSumData.Min[stIdx])
+			stationSumData.Max[stIdx] = max(stationData.Max[idx], stationSumData.Max[stIdx])
+		}
+	}
+
+	result <- tableResultType{
+		Temps: stationSumData,
+		Table: sumTable,
+	}
+}
+
+func processChunkTable(content []byte, channel chan tableResultType) {
+	stationData := onebrc.NewStationTemperatures(10_000)
+	table := onebrc.NewStationTable(onebrc.DefaultTableBits, onebrc.DefaultLoadFactor, 0)
+
+	station := [100]byte{}
+	// We suppose the file is valid, without a single error.
+	// Not a single error check is made.
+	for len(content) > 0 {
+
+		// Station name is not empty.
+		semiColonIdx := 1
+		station[0] = content[0]
+		currByte := content[1]
+		var nameHash uint32 = fnvOffsetBasis
+		for currByte != ';' {
+			station[semiColonIdx] = currByte
+			nameHash ^= uint32(currByte)
+			nameHash *= fnvPrime
+			semiColonIdx++
+			currByte = content[semiColonIdx]
+		}
+		var temperature int = 0
+		negate := 1
+		if content[semiColonIdx+1] == '-' {
+			negate = -1
+			content = content[semiColonIdx+2:]
+		} else {
+			content = content[semiColonIdx+1:]
+		}
+
+		// Either `N.N\n` or `NN.N\n`
+		if content[1] == '.' {
+			temperature = negate * (int(content[0])*10 + int(content[2]) - 528)
+			content = content[4:]
+		} else {
+			temperature = negate * (int(content[0])*100 + int(content[1])*10 + int(content[3]) - 5328)
+			content = content[5:]
+		}
+
+		stIdx, isNew := table.Index(station[:semiColonIdx], nameHash)
+		if isNew {
+			stationData.Reserve(stIdx)
+			stationData.TempSum[stIdx] = temperature
+			stationData.Count[stIdx] = 1
+			stationData.Min[stIdx] = temperature
+			stationData.Max[stIdx] = temperature
+			continue
+		}
+		stationData.TempSum[stIdx] += temperature
+		stationData.Count[stIdx]++
+		stationData.Min[stIdx] = min(stationData.Min[stIdx], temperature)
+		stationData.Max[stIdx] = max(stationData.Max[stIdx], temperature)
+	}
+	channel <- tableResultType{Temps: stationData, Table: table}
+}
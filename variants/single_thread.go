@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     variants/single_thread.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package variants
+
+import (
+	"bytes"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+)
+
+type stationTemperature struct {
+	TempSum int32
+	Count   uint32
+	Min     int16
+	Max     int16
+}
+
+func singleThread(fileName string) (onebrc.Results, error) {
+	content, err := readFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	stationData := make(map[string]stationTemperature, 10_000)
+	idx := 0
+	// We suppose the file is valid, without a single error.
+	// Not a single error check is made.
+	for idx < len(content) {
+		semiColonIdx := bytes.IndexByte(content[idx:], ';')
+		if semiColonIdx < 0 {
+			break
+		}
+		station := content[idx : idx+semiColonIdx]
+		newLineIdx := bytes.IndexByte(content[idx+semiColonIdx:], '\n')
+		// End of file.
+		if newLineIdx < 0 {
+			newLineIdx = len(content) - (idx + semiColonIdx)
+		}
+		var temperature int16 = 0
+		var negate int16 = 1
+		for tmpIdx := idx + semiColonIdx + 1; tmpIdx < idx+semiColonIdx+newLineIdx; {
+			currByte := content[tmpIdx]
+			if currByte == '-' {
+				negate = -1
+			} else if currByte != '.' {
+				intVal := currByte - '0'
+				temperature = temperature*10 + int16(intVal)
+			}
+			tmpIdx++
+		}
+		temperature *= negate
+
+		currData, ok := stationData[string(station)]
+		if ok {
+			currData.TempSum += int32(temperature)
+			currData.Count++
+			currData.Min = min(currData.Min, temperature)
+			currData.Max = max(currData.Max, temperature)
+			stationData[string(station)] = currData
+		} else {
+			stationData[string(station)] = stationTemperature{
+				TempSum: int32(temperature),
+				Count:   1,
+				Min:     temperature,
+				Max:     temperature,
+			}
+		}
+
+		idx += semiColonIdx + newLineIdx + 1
+	}
+
+	results := make(onebrc.Results, 0, len(stationData))
+	for station, data := range stationData {
+		results = append(results, onebrc.Station{
+			Name:  station,
+			Min:   int(data.Min),
+			Max:   int(data.Max),
+			Sum:   int(data.TempSum),
+			Count: uint(data.Count),
+		})
+	}
+	return sortResults(results), nil
+}
@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     variants/single_thread_arrays.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package variants
+
+import (
+	"bytes"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+)
+
+type smallStationTemperatures struct {
+	TempSum []int32
+	Count   []uint32
+	Min     []int16
+	Max     []int16
+}
+
+// Reserve makes room for the station with index `idx`, like
+// onebrc.StationTemperatures.Reserve.
+func (s *smallStationTemperatures) Reserve(idx int) {
+	if idx < len(s.Count) {
+		return
+	}
+	numNew := max(idx+1, 2*len(s.Count)) - len(s.Count)
+	s.TempSum = append(s.TempSum, make([]int32, numNew)...)
+	s.Count = append(s.Count, make([]uint32, numNew)...)
+	s.Min = append(s.Min, make([]int16, numNew)...)
+	s.Max = append(s.Max, make([]int16, numNew)...)
+}
+
+func singleThreadArrays(fileName string) (onebrc.Results, error) {
+	content, err := readFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	stationData := smallStationTemperatures{
+		TempSum: make([]int32, 10_000),
+		Count:   make([]uint32, 10_000),
+		Min:     make([]int16, 10_000),
+		Max:     make([]int16, 10_000),
+	}
+
+	stationIdxMap := make(map[string]int, 10_000)
+	stationIdx := 0
+	idx := 0
+	// We suppose the file is valid, without a single error.
+	// Not a single error check is made.
+	for idx < len(content) {
+		semiColonIdx := bytes.IndexByte(content[idx:], ';')
+		// End of file.
+		if semiColonIdx < 0 {
+			break
+		}
+		station := content[idx : idx+semiColonIdx]
+		newLineIdx := bytes.IndexByte(content[idx+semiColonIdx:], '\n')
+		// End of file.
+		if newLineIdx < 0 {
+			newLineIdx = len(content) - (idx + semiColonIdx)
+		}
+		var temperature int16 = 0
+		var negate int16 = 1
+		for tmpIdx := idx + semiColonIdx + 1; tmpIdx < idx+semiColonIdx+newLineIdx; {
+			currByte := content[tmpIdx]
+			if currByte == '-' {
+				negate = -1
+			} else if currByte != '.' {
+				intVal := currByte - '0'
+				temperature = temperature*10 + int16(intVal)
+			}
+			tmpIdx++
+		}
+		temperature *= negate
+
+		stIdx, ok := stationIdxMap[string(station)]
+		if ok {
+			stationData.TempSum[stIdx] += int32(temperature)
+			stationData.Count[stIdx]++
+			stationData.Min[stIdx] = min(stationData.Min[stIdx], temperature)
+			stationData.Max[stIdx] = max(stationData.Max[stIdx], temperature)
+		} else {
+			stationIdxMap[string(station)] = stationIdx
+			stationData.Reserve(stationIdx)
+			stationData.TempSum[stationIdx] += int32(temperature)
+			stationData.Count[stationIdx]++
+			stationData.Min[stationIdx] = temperature
+			stationData.Max[stationIdx] = temperature
+			stationIdx++
+		}
+
+		idx += semiColonIdx + newLineIdx + 1
+	}
+
+	results := make(onebrc.Results, 0, len(stationIdxMap))
+	for station, idx := range stationIdxMap {
+		results = append(results, onebrc.Station{
+			Name:  station,
+			Min:   int(stationData.Min[idx]),
+			Max:   int(stationData.Max[idx]),
+			Sum:   int(stationData.TempSum[idx]),
+			Count: uint(stationData.Count[idx]),
+		})
+	}
+	return sortResults(results), nil
+}
@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     variants/single_thread_arrays_single_parse.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package variants
+
+import (
+	"bytes"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+)
+
+func singleThreadArraysSingleParse(fileName string) (onebrc.Results, error) {
+	content, err := readFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	stationData := onebrc.NewStationTemperatures(10_000)
+
+	stationIdxMap := make(map[string]int, 10_000)
+	stationIdx := 0
+	idx := 0
+	// We suppose the file is valid, without a single error.
+	// Not a single error check is made.
+	for idx < len(content) {
+		semiColonIdx := bytes.IndexByte(content[idx:], ';')
+		// End of file.
+		if semiColonIdx < 0 {
+			break
+		}
+		station := content[idx : idx+semiColonIdx]
+		var temperature int = 0
+		var negate int = 1
+		tmpIdx := idx + semiColonIdx + 1
+		newLineIdx := 0
+	Loop:
+		for tmpIdx < len(content) {
+			currByte := content[tmpIdx]
+			tmpIdx++
+			newLineIdx++
+			switch currByte {
+			case '-':
+				negate = -1
+			case '\n':
+				break Loop
+			case '.':
+				continue
+			default:
+				intVal := currByte - '0'
+				temperature = temperature*10 + int(intVal)
+			}
+		}
+		temperature *= negate
+
+		stIdx, ok := stationIdxMap[string(station)]
+		if ok {
+			stationData.TempSum[stIdx] += temperature
+			stationData.Count[stIdx]++
+			stationData.Min[stIdx] = min(stationData.Min[stIdx], temperature)
+			stationData.Max[stIdx] = max(stationData.Max[stIdx], temperature)
+		} else {
+			stationIdxMap[string(station)] = stationIdx
+			stationData.Reserve(stationIdx)
+			stationData.TempSum[stationIdx] += temperature
+			stationData.Count[stationIdx]++
+			stationData.Min[stationIdx] = temperature
+			stationData.Max[stationIdx] = temperature
+			stationIdx++
+		}
+
+		idx += semiColonIdx + newLineIdx + 1
+	}
+
+	return resultsFromMap(stationIdxMap, stationData), nil
+}
@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     variants/single_thread_parsing.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package variants
+
+import "github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+
+func singleThreadParsing(fileName string) (onebrc.Results, error) {
+	content, err := readFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	stationData, stationIdxMap := processChunk(content)
+
+	return resultsFromMap(stationIdxMap, stationData), nil
+}
+
+// processChunk is the parser of go_single_thread_parsing.go, which is used
+// unchanged by the first parallel versions.
+func processChunk(content []byte) (onebrc.StationTemperatures, map[string]int) {
+	stationData := onebrc.NewStationTemperatures(10_000)
+
+	stationIdxMap := make(map[string]int, 10_000)
+	stationIdx := 0
+
+	// We suppose the file is valid, without a single error.
+	// Not a single error check is made.
+	for len(content) > 0 {
+		station := [100]byte{}
+
+		// Station name is not empty.
+		semiColonIdx := 1
+		station[0] = content[0]
+		currByte := content[1]
+		for currByte != ';' {
+			station[semiColonIdx] = currByte
+			semiColonIdx++
+			currByte = content[semiColonIdx]
+		}
+		var temperature int = 0
+		var negate = false
+		if content[semiColonIdx+1] == '-' {
+			negate = true
+			content = content[semiColonIdx+2:]
+		} else {
+			content = content[semiColonIdx+1:]
+		}
+
+		// Either `N.N\n` or `NN.N\n`
+		if content[1] == '.' {
+			temperature = int(content[0])*10 + int(content[2]) - '0'*11
+			content = content[4:]
+		} else {
+			temperature = int(content[0])*100 + int(content[1])*10 + int(content[3]) - '0'*111
+			content = content[5:]
+		}
+		if negate {
+			temperature *= -1
+		}
+
+		stIdx, ok := stationIdxMap[string(station[:semiColonIdx])]
+		if ok {
+			stationData.TempSum[stIdx] += temperature
+			stationData.Count[stIdx]++
+			stationData.Min[stIdx] = min(stationData.Min[stIdx], temperature)
+			stationData.Max[stIdx] = max(stationData.Max[stIdx], temperature)
+		} else {
+			stationIdxMap[string(station[:semiColonIdx])] = stationIdx
+			stationData.Reserve(stationIdx)
+			stationData.TempSum[stationIdx] = temperature
+			stationData.Count[stationIdx] = 1
+			stationData.Min[stationIdx] = temperature
+			stationData.Max[stationIdx] = temperature
+			stationIdx++
+		}
+	}
+	return stationData, stationIdxMap
+}
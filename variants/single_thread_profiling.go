@@ -0,0 +1,35 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     variants/single_thread_profiling.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package variants
+
+import "github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+
+func singleThreadProfiling(fileName string) (onebrc.Results, error) {
+	content, err := readFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	stationData := onebrc.NewStationTemperatures(10_000)
+
+	stationIdxMap := make(map[string]int, 10_000)
+	stationIdx := 0
+	idx := 0
+	// We suppose the file is valid, without a single error.
+	// Not a single error check is made.
+	for idx < len(content) {
+		semiColonIdx, station := onebrc.ParseStationName(content, idx)
+		temperature, newLineIdx := onebrc.ParseTemperature(idx, semiColonIdx, content)
+		stationIdx = onebrc.AddTemperatureData(stationIdxMap, station, &stationData, temperature, stationIdx)
+		idx += semiColonIdx + newLineIdx + 1
+	}
+
+	return resultsFromMap(stationIdxMap, stationData), nil
+}
@@ -0,0 +1,263 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     variants/variants.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+// Package variants contains all Go versions of the solution, from the first
+// single threaded go_single_thread.go to the fastest go_parallel_eq.go, so
+// they can be run and compared using the same program.
+//
+// Each variant is a port of the `main` function of the Go file with the same
+// name, which returns the results instead of printing them.
+package variants
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+)
+
+// Variant is one of the Go versions of the solution.
+type Variant interface {
+	// Name returns the name used to select the variant.
+	Name() string
+	// Description returns a short description of the variant.
+	Description() string
+	// Run calculates the minimum, mean and maximum temperature of each weather
+	// station in the file `fileName`.
+	Run(fileName string) (onebrc.Results, error)
+}
+
+// OptionsVariant is a Variant which supports the options of the onebrc
+// package.
+type OptionsVariant interface {
+	Variant
+	// RunOptions is Run using the options `opts`.
+	RunOptions(fileName string, opts onebrc.Options) (onebrc.Results, error)
+	// RunReader is RunOptions reading the data from `r`.
+	RunReader(r io.Reader, opts onebrc.Options) (onebrc.Results, error)
+}
+
+type variant struct {
+	name        string
+	description string
+	run         func(fileName string) (onebrc.Results, error)
+}
+
+func (v variant) Name() string {
+	return v.name
+}
+
+func (v variant) Description() string {
+	return v.description
+}
+
+func (v variant) Run(fileName string) (onebrc.Results, error) {
+	return v.run(fileName)
+}
+
+// libraryVariant is the variant implemented by the onebrc package.
+type libraryVariant struct {
+	variant
+}
+
+func (v libraryVariant) RunOptions(fileName string, opts onebrc.Options) (onebrc.Results, error) {
+	return onebrc.Aggregate(fileName, opts)
+}
+
+func (v libraryVariant) RunReader(r io.Reader, opts onebrc.Options) (onebrc.Results, error) {
+	return onebrc.AggregateReader(r, opts)
+}
+
+// Default is the name of the fastest variant.
+const Default = "parallel-eq"
+
+// The variants in the order of their development.
+var variants = []Variant{
+	variant{
+		name:        "single",
+		description: "go_single_thread.go: single threaded, using a map of structures",
+		run:         singleThread,
+	},
+	variant{
+		name:        "single-arrays",
+		description: "go_single_thread_arrays.go: a map of indices into a structure of arrays",
+		run:         singleThreadArrays,
+	},
+	variant{
+		name:        "single-arrays-64bit",
+		description: "go_single_thread_arrays_64bit_ints.go: as above, using 64 bit integers",
+		run:         singleThreadArrays64Bit,
+	},
+	variant{
+		name:        "single-arrays-single-parse",
+		description: "go_single_thread_arrays_single_parse.go: as above, not searching for the newline",
+		run:         singleThreadArraysSingleParse,
+	},
+	variant{
+		name:        "single-parse-ii",
+		description: "go_single_thread_single_parse_II.go: as above, not searching for the semicolon",
+		run:         singleThreadSingleParseII,
+	},
+	variant{
+		name:        "single-profiling",
+		description: "go_single_thread_profiling.go: as above, using the building blocks of the onebrc package, see `--cpuprofile`",
+		run:         singleThreadProfiling,
+	},
+	variant{
+		name:        "single-parsing",
+		description: "go_single_thread_parsing.go: as above, changed the parsing of the station name and temperature",
+		run:         singleThreadParsing,
+	},
+	variant{
+		name:        "parallel-preparation",
+		description: "go_parallel_preparation.go: as above, processing the file in \"number of cores\" chunks",
+		run:         parallelPreparation,
+	},
+	variant{
+		name:        "parallel",
+		description: "go_parallel.go: as above, using \"number of cores\" goroutines",
+		run:         parallel,
+	},
+	variant{
+		name:        "parallel-thread-factor",
+		description: "go_parallel_thread_factor.go: as above, using 2 * \"number of cores\" goroutines",
+		run:         parallelThreadFactor,
+	},
+	variant{
+		name:        "parallel-ii",
+		description: "go_parallel_II.go: as above, using 20 * \"number of cores\" goroutines and 2 to sum the results",
+		run:         parallelII,
+	},
+	variant{
+		name:        "parallel-iii",
+		description: "go_parallel_III.go: as above, moving the name array out of the loop and non-blocking channels",
+		run:         parallelIII,
+	},
+	variant{
+		name:        "parallel-fnv",
+		description: "go_parallel_fnv.go: as above, using the FNV hash function and mmap",
+		run:         parallelFNV,
+	},
+	libraryVariant{variant{
+		name:        "parallel-eq",
+		description: "go_parallel_eq.go: as above, using bytes.Equal and 10 * \"number of cores\" goroutines",
+		run:         parallelEq,
+	}},
+	variant{
+		name:        "parallel-table",
+		description: "parallel-fnv using the growing, open addressing hash table of the onebrc package",
+		run:         parallelTable,
+	},
+}
+
+// All returns all variants in the order of their development.
+func All() []Variant {
+	return append([]Variant(nil), variants...)
+}
+
+// Names returns the names of all variants.
+func Names() []string {
+	names := make([]string, 0, len(variants))
+	for _, v := range variants {
+		names = append(names, v.Name())
+	}
+	return names
+}
+
+// Get returns the variant with the name `name`.
+func Get(name string) (Variant, error) {
+	for _, v := range variants {
+		if v.Name() == name {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown variant '%s', valid variants are: %s", name, strings.Join(Names(), ", "))
+}
+
+// readFile returns the content of the file `fileName`. Most variants need a
+// newline at the end of the last line, so one is added if it is missing.
+func readFile(fileName string) ([]byte, error) {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		content = append(content, '\n')
+	}
+	return content, nil
+}
+
+// resultsFromMap returns the stations of `stationIdxMap` sorted by name.
+func resultsFromMap(stationIdxMap map[string]int, stationData onebrc.StationTemperatures) onebrc.Results {
+	return onebrc.ResultsFromMap(onebrc.SortStationNames(stationIdxMap), stationIdxMap, stationData)
+}
+
+// sortResults sorts the results by station name.
+func sortResults(results onebrc.Results) onebrc.Results {
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
+
+type chunk struct {
+	StartIdx int64
+	EndIdx   int64
+}
+
+func generateChunkIndices(numCPUs int, size int64, file *os.File, fileName string) ([]chunk, error) {
+	chunkList := make([]chunk, 0, numCPUs)
+	if size == 0 {
+		return chunkList, nil
+	}
+	chunkSize := size / int64(numCPUs)
+	chunkList = append(chunkList, chunk{
+		StartIdx: 0,
+		EndIdx:   size - 1,
+	})
+
+	buffer := make([]byte, 150)
+	for cpuIdx := 1; cpuIdx < numCPUs; cpuIdx++ {
+		// Chunks smaller than a line would start in the previous chunk.
+		readOff := max(int64(cpuIdx)*chunkSize, chunkList[cpuIdx-1].StartIdx)
+		n, err := file.ReadAt(buffer, readOff)
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("error reading file '%s' for chunking: %w", fileName, err)
+		}
+		newlineIdx := bytes.IndexByte(buffer[:n], '\n')
+		if newlineIdx < 0 || readOff+int64(newlineIdx) >= size-1 {
+			break
+		}
+		chunkList = append(chunkList, chunk{
+			StartIdx: readOff + int64(newlineIdx) + 1,
+			EndIdx:   size - 1,
+		})
+		chunkList[cpuIdx-1].EndIdx = readOff + int64(newlineIdx)
+	}
+	return chunkList, nil
+}
+
+// readChunk reads the chunk `chunk` of `file`. A newline is added, if the last
+// line of the file has none.
+func readChunk(chunk chunk, file *os.File) ([]byte, error) {
+	content := make([]byte, chunk.EndIdx-chunk.StartIdx+1, chunk.EndIdx-chunk.StartIdx+2)
+	_, err := file.ReadAt(content, chunk.StartIdx)
+	if err != nil {
+		return nil, fmt.Errorf("error reading data file at offset %d, len %d: %w",
+			chunk.StartIdx, chunk.EndIdx-chunk.StartIdx+1, err)
+	}
+	if content[len(content)-1] != '\n' {
+		content = append(content, '\n')
+	}
+	return content, nil
+}
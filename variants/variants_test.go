@@ -0,0 +1,258 @@
+// SPDX-FileCopyrightText:  Copyright 2024 Roland Csaszar
+// SPDX-License-Identifier: MIT
+//
+// Project:  1-billion-row-challenge
+// File:     variants/variants_test.go
+// Date:     17.Oct.2026
+//
+// =============================================================================
+
+package variants_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Release-Candidate/1-billion-row-challenge/generate"
+	"github.com/Release-Candidate/1-billion-row-challenge/onebrc"
+	"github.com/Release-Candidate/1-billion-row-challenge/reference"
+	"github.com/Release-Candidate/1-billion-row-challenge/variants"
+)
+
+type testCase struct {
+	name    string
+	content string
+}
+
+// The test cases, the generated ones are added by TestMain.
+var testCases = []testCase{
+	{name: "empty", content: ""},
+	{name: "one line", content: "Hamburg;12.0\n"},
+	{name: "one line without newline", content: "Hamburg;12.0"},
+	{name: "no trailing newline", content: "Hamburg;12.0\nBulawayo;8.9\nPalembang;38.8\nHamburg;-3.4"},
+	{name: "all negative", content: "Oslo;-0.1\nOslo;-12.3\nVostok;-99.9\nVostok;-89.2\nVostok;-5.0\nOslo;-1.0\n"},
+	{name: "min and max", content: "a;-99.9\na;99.9\nb;0.0\nb;-0.0\nc;9.9\nc;-9.9\n"},
+	{name: "rounding ties", content: "pos;0.2\npos;0.3\nneg;-0.2\nneg;-0.3\nzero;-0.1\nzero;0.0\n"},
+	{name: "utf-8 names", content: "Zürich;1.2\nİzmir;23.4\n東京;-5.6\nZürich;-7.8\nSão Paulo;25.0\n"},
+	{name: "single station", content: strings.Repeat("Tokyo;35.6\nTokyo;-35.6\nTokyo;0.1\n", 1000)},
+}
+
+// The weather stations of the generated data, read by TestMain.
+var stations []generate.Station
+
+func TestMain(m *testing.M) {
+	var err error
+	stations, err = generate.ReadStationsFile(filepath.Join("..", generate.DefaultStationsFile))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	for _, opts := range []generate.Options{
+		{Rows: 100_000, Seed: 1},
+		{Rows: 100_000, Seed: 2, Distribution: generate.DistributionGaussian},
+		{Rows: 1_000, Seed: 3, Distribution: generate.DistributionGaussian, StdDev: 50},
+		{Rows: 50_000, Seed: 4, Profile: generate.Profile10K},
+		{Rows: 50_000, Seed: 5, Profile: generate.ProfileLongNames},
+		{Rows: 50_000, Seed: 6, Profile: generate.ProfileZipf},
+		// Every line has to search through the colliding names, so less rows.
+		{Rows: 20_000, Seed: 7, Profile: generate.ProfileFNVCollide},
+	} {
+		opts.Stations = stations
+		var buffer bytes.Buffer
+		_, err = generate.Generate(&buffer, opts)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		testCases = append(testCases, testCase{
+			name:    fmt.Sprintf("generated %d rows %s %s seed %d", opts.Rows, opts.Profile, opts.Distribution, opts.Seed),
+			content: buffer.String(),
+		})
+	}
+
+	// More stations than the rules allow and than the 2^16 slots of the hash
+	// tables, so the station arrays and the tables have to grow.
+	var many strings.Builder
+	for idx := range 100_000 {
+		fmt.Fprintf(&many, "Station %d;%d.%d\n", idx, idx%199-99, idx%10)
+		fmt.Fprintf(&many, "Station %d;%d.%d\n", idx, -(idx % 97), idx%7)
+	}
+	testCases = append(testCases, testCase{name: "100,000 stations", content: many.String()})
+
+	// TestVariants writes the data files to the working directory.
+	dir, err := os.MkdirTemp("", "variants")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	err = os.Chdir(dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// TestVariants runs all variants on all test cases and checks their results
+// against the reference solution.
+func TestVariants(t *testing.T) {
+	for idx, tc := range testCases {
+		fileName := fmt.Sprintf("measurements_%d.txt", idx)
+		err := os.WriteFile(fileName, []byte(tc.content), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, err := reference.Solve(strings.NewReader(tc.content))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, variant := range variants.All() {
+			t.Run(tc.name+"/"+variant.Name(), func(t *testing.T) {
+				results, err := variant.Run(fileName)
+				if err != nil {
+					t.Fatal(err)
+				}
+				checkResults(t, expected, results)
+			})
+		}
+	}
+}
+
+// TestOptionsVariants runs the variants supporting options reading from a
+// stream and checking the lines.
+func TestOptionsVariants(t *testing.T) {
+	for _, tc := range testCases {
+		expected, err := reference.Solve(strings.NewReader(tc.content))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, variant := range variants.All() {
+			optsVariant, ok := variant.(variants.OptionsVariant)
+			if !ok {
+				continue
+			}
+			for _, mode := range []onebrc.Mode{onebrc.ModeFast, onebrc.ModeStrict, onebrc.ModeLenient} {
+				t.Run(fmt.Sprintf("%s/%s/mode %d", tc.name, variant.Name(), mode), func(t *testing.T) {
+					results, err := optsVariant.RunReader(strings.NewReader(tc.content), onebrc.Options{Mode: mode})
+					if mode == onebrc.ModeStrict && len(expected) > onebrc.MaxStations {
+						if !errors.Is(err, onebrc.ErrTooManyStations) {
+							t.Fatalf("got error %v, want %v", err, onebrc.ErrTooManyStations)
+						}
+						return
+					}
+					if err != nil {
+						t.Fatal(err)
+					}
+					checkResults(t, expected, results)
+				})
+			}
+		}
+	}
+}
+
+// TestHashers runs the variants supporting options using all hash functions,
+// with the fast and the checking parser.
+func TestHashers(t *testing.T) {
+	for _, tc := range testCases {
+		expected, err := reference.Solve(strings.NewReader(tc.content))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, variant := range variants.All() {
+			optsVariant, ok := variant.(variants.OptionsVariant)
+			if !ok {
+				continue
+			}
+			for _, hasherName := range onebrc.HasherNames() {
+				hasher, err := onebrc.ParseHasher(hasherName)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, mode := range []onebrc.Mode{onebrc.ModeFast, onebrc.ModeLenient} {
+					t.Run(fmt.Sprintf("%s/%s/%s/mode %d", tc.name, variant.Name(), hasherName, mode), func(t *testing.T) {
+						// `Station N` only has a few different lengths and a
+						// single prefix, so all lookups search the same few long
+						// probe sequences.
+						if hasherName == "length-prefix" && len(expected) > onebrc.MaxStations {
+							t.Skip("too slow using length-prefix")
+						}
+						results, err := optsVariant.RunReader(strings.NewReader(tc.content), onebrc.Options{Mode: mode, Hasher: hasher})
+						if err != nil {
+							t.Fatal(err)
+						}
+						checkResults(t, expected, results)
+					})
+				}
+			}
+		}
+	}
+}
+
+// TestFloodResistant runs the variants supporting options resisting hash
+// flooding and using length-prefix with a limited probe length, which is fast
+// enough for all test cases then.
+func TestFloodResistant(t *testing.T) {
+	lengthPrefix, err := onebrc.ParseHasher("length-prefix")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range testCases {
+		expected, err := reference.Solve(strings.NewReader(tc.content))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, variant := range variants.All() {
+			optsVariant, ok := variant.(variants.OptionsVariant)
+			if !ok {
+				continue
+			}
+			for _, optsCase := range []struct {
+				name string
+				opts onebrc.Options
+			}{
+				{"flood-resistant", onebrc.Options{FloodResistant: true}},
+				{"flood-resistant lenient", onebrc.Options{FloodResistant: true, Mode: onebrc.ModeLenient}},
+				{"length-prefix max-probe", onebrc.Options{Hasher: lengthPrefix, MaxProbe: 4}},
+			} {
+				t.Run(tc.name+"/"+variant.Name()+"/"+optsCase.name, func(t *testing.T) {
+					results, err := optsVariant.RunReader(strings.NewReader(tc.content), optsCase.opts)
+					if err != nil {
+						t.Fatal(err)
+					}
+					checkResults(t, expected, results)
+				})
+			}
+		}
+	}
+}
+
+func checkResults(t *testing.T, expected onebrc.Summaries, results onebrc.Results) {
+	t.Helper()
+	for _, difference := range onebrc.Compare(expected, results.Summaries(), 0) {
+		t.Error(difference)
+	}
+
+	var want, got strings.Builder
+	err := expected.Write(&want, onebrc.Format1BRC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = results.Print(&got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != want.String() {
+		t.Errorf("got output\n%.200s\nwant\n%.200s", got.String(), want.String())
+	}
+}